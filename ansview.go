@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// ANSI art viewer (--view FILE, ":view")
+//------------------------------------------------------------------------------
+
+// artFile is an .ans or .nfo file open for viewing, scrolled to top.
+type artFile struct {
+	name   string
+	screen banner.Screen
+	top    int
+}
+
+func openArtFile(path string) (*artFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &artFile{name: filepath.Base(path), screen: banner.ParseANS(data)}, nil
+}
+
+// artRows is how many rows of the file fit the terminal, below the title.
+func (m model) artRows() int { return max(1, m.h-2) }
+
+// updateArtFile scrolls the open file; q or esc closes it.
+func (m model) updateArtFile(msg tea.KeyMsg) (model, tea.Cmd) {
+	a, rows := m.artFile, m.artRows()
+	last := max(0, len(a.screen.Rows)-rows)
+	switch msg.String() {
+	case "q", "esc":
+		m.artFile = nil
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		a.top--
+	case "down", "j", "enter":
+		a.top++
+	case "pgup", "b":
+		a.top -= rows
+	case "pgdown", " ", "f":
+		a.top += rows
+	case "home", "g":
+		a.top = 0
+	case "end", "G":
+		a.top = last
+	}
+	a.top = min(max(0, a.top), last)
+	return m, nil
+}
+
+// artFileView shows a screenful of the file under a title line.
+func (m model) artFileView() string {
+	a, r := m.artFile, m.v.Renderer()
+	title := a.name
+	if s := a.screen.Sauce; s != nil && s.Title != "" {
+		title = fmt.Sprintf("%s by %s", s.Title, s.Author)
+		if s.Group != "" {
+			title += " / " + s.Group
+		}
+	}
+	pos := fmt.Sprintf("  %d-%d of %d  (↑/↓, pgup/pgdn, q to close)", a.top+1, min(a.top+m.artRows(), len(a.screen.Rows)), len(a.screen.Rows))
+	lines := append([]string{r.NewStyle().Bold(true).Render(title) + r.NewStyle().Faint(true).Render(pos)},
+		a.screen.Lines(r, a.top, m.artRows())...)
+	return r.NewStyle().MaxWidth(m.w).Render(strings.Join(lines, "\n")) // wider art is cut off
+}
+
+func cmdView(m *model, args []string) (string, tea.Cmd) {
+	if m.public {
+		return "view: not available in remote sessions", nil
+	}
+	if len(args) == 0 {
+		return "usage: view FILE", nil
+	}
+	a, err := openArtFile(strings.Join(args, " "))
+	if err != nil {
+		return "view: " + err.Error(), nil
+	}
+	m.artFile = a
+	return "", nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"image"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Image backdrops (--backdrop PATH)
+//------------------------------------------------------------------------------
+
+const (
+	defaultBackdropDim = 0.6
+	backdropMargin     = 4 // columns each side of the banner; half as many rows
+)
+
+// backdrop is a picture drawn behind the banner in render exports and the
+// serve overlay.
+type backdrop struct {
+	img   image.Image
+	dim   float64
+	solid bool // blocks rather than ASCII
+
+	drawn banner.Frame // last drawing, reused while the size holds
+}
+
+// loadBackdrop reads the --backdrop flags, returning nil when none is set.
+func loadBackdrop(c cliFlags) (*backdrop, error) {
+	if c.backdrop == "" {
+		return nil, nil
+	}
+	if err := checkImageStyle(c.backdropStyle); err != nil {
+		return nil, fmt.Errorf("backdrop: %w", err)
+	}
+	if c.backdropDim < 0 || c.backdropDim > 1 {
+		return nil, fmt.Errorf("backdrop-dim %g out of range (0-1)", c.backdropDim)
+	}
+	img, err := banner.LoadImage(c.backdrop)
+	if err != nil {
+		return nil, fmt.Errorf("backdrop: %w", err)
+	}
+	return &backdrop{img: img, dim: c.backdropDim, solid: c.backdropStyle == "color"}, nil
+}
+
+// behind puts f on the backdrop. The backdrop fills a width by height
+// canvas, or with no canvas, the banner plus a margin.
+func (b *backdrop) behind(f banner.Frame, width, height int) banner.Frame {
+	if width <= 0 || height <= 0 {
+		width, height = f.Width+2*backdropMargin, f.Height+backdropMargin
+	}
+	if b.drawn.Width != width || b.drawn.Height != height {
+		b.drawn = banner.ImageFrame(b.img, width, height, b.solid)
+	}
+	return f.Over(b.drawn, b.dim)
+}
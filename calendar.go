@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Calendar feed (--calendar[=FORMAT], --month)
+//------------------------------------------------------------------------------
+
+const defaultCalendarFormat = "%a %d %b"
+
+// calendarFeed shows today's date, and with month set this month's
+// calendar in plain text below it, for a dashboard pane.
+type calendarFeed struct {
+	format string
+	month  bool
+}
+
+func (calendarFeed) name() string { return "calendar" }
+
+func (c calendarFeed) text(now time.Time) (string, error) { return strftime(c.format, now) }
+
+// next is the coming local midnight.
+func (calendarFeed) next(now time.Time) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+}
+
+func (c calendarFeed) caption(now time.Time) string {
+	if !c.month {
+		return ""
+	}
+	return monthCalendar(now)
+}
+
+// monthCalendar lays out the month of t like cal(1): a title, then weeks
+// from Sunday. Lines are padded to one width so they stay aligned when
+// centered.
+func monthCalendar(t time.Time) string {
+	const width = 20
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	days := first.AddDate(0, 1, -1).Day()
+
+	title := first.Format("January 2006")
+	lines := []string{strings.Repeat(" ", (width-len(title))/2) + title, "Su Mo Tu We Th Fr Sa"}
+	var week strings.Builder
+	week.WriteString(strings.Repeat("   ", int(first.Weekday())))
+	for d := 1; d <= days; d++ {
+		fmt.Fprintf(&week, "%2d", d)
+		if time.Date(t.Year(), t.Month(), d, 0, 0, 0, 0, t.Location()).Weekday() == time.Saturday || d == days {
+			lines = append(lines, week.String())
+			week.Reset()
+		} else {
+			week.WriteByte(' ')
+		}
+	}
+	for i, l := range lines {
+		lines[i] = l + strings.Repeat(" ", width-len(l))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Canvas edit mode (ctrl+e, ":canvas")
+//------------------------------------------------------------------------------
+
+const (
+	canvasMarginX = 2 // free columns each side of the art, room to nudge into
+	canvasMarginY = 1
+)
+
+// canvas is a copy of the art being touched up by hand, with a cursor.
+type canvas struct {
+	rows [][]rune
+	x, y int
+}
+
+// newCanvas copies art onto a canvas with a margin all round, the cursor
+// on the art's top left cell.
+func newCanvas(art banner.Art) *canvas {
+	w, h := art.Width+2*canvasMarginX, len(art.Rows)+2*canvasMarginY
+	c := &canvas{rows: make([][]rune, h), x: canvasMarginX, y: canvasMarginY}
+	for y := range c.rows {
+		c.rows[y] = []rune(strings.Repeat(" ", w))
+		if src := y - canvasMarginY; src >= 0 && src < len(art.Rows) {
+			copy(c.rows[y][canvasMarginX:], art.Rows[src])
+		}
+	}
+	return c
+}
+
+func (c *canvas) width() int { return len(c.rows[0]) }
+
+// art is a copy of the canvas as Art.
+func (c *canvas) art() banner.Art {
+	a := banner.Art{Rows: make([][]rune, len(c.rows)), Width: c.width()}
+	for y, row := range c.rows {
+		a.Rows[y] = append([]rune(nil), row...)
+	}
+	return a
+}
+
+// move puts the cursor dx, dy cells away, stopping at the edges.
+func (c *canvas) move(dx, dy int) {
+	c.x = min(max(0, c.x+dx), c.width()-1)
+	c.y = min(max(0, c.y+dy), len(c.rows)-1)
+}
+
+// stamp writes r under the cursor and steps right, as typing does.
+func (c *canvas) stamp(r rune) {
+	c.rows[c.y][c.x] = r
+	c.move(1, 0)
+}
+
+func (c *canvas) erase() { c.rows[c.y][c.x] = ' ' }
+
+// nudge shifts everything drawn dx, dy cells, the cursor with it. It
+// refuses, returning false, if anything would go over the edge.
+func (c *canvas) nudge(dx, dy int) bool {
+	w, h := c.width(), len(c.rows)
+	for y, row := range c.rows {
+		for x, r := range row {
+			if r != ' ' && (x+dx < 0 || x+dx >= w || y+dy < 0 || y+dy >= h) {
+				return false
+			}
+		}
+	}
+	moved := make([][]rune, h)
+	for y := range moved {
+		moved[y] = []rune(strings.Repeat(" ", w))
+	}
+	for y, row := range c.rows {
+		for x, r := range row {
+			if r != ' ' {
+				moved[y+dy][x+dx] = r
+			}
+		}
+	}
+	c.rows = moved
+	c.move(dx, dy)
+	return true
+}
+
+// text is the canvas as plain lines, without the blank rows above and below
+// or trailing blanks.
+func (c *canvas) text() string {
+	var b strings.Builder
+	for _, row := range c.rows {
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteByte('\n')
+	}
+	return strings.Trim(b.String(), "\n") + "\n"
+}
+
+// startEditing turns the art shown into a canvas, or picks up the one
+// already being edited.
+func (m *model) startEditing() {
+	if m.pictureView != "" {
+		m.status = "canvas: color pictures can't be edited (try :image ascii)"
+		return
+	}
+	if m.canvas == nil {
+		m.canvas = newCanvas(m.v.Art())
+	}
+	m.editing = true
+	m.showCanvas()
+}
+
+// showCanvas puts the canvas in place of the banner, cursor and all.
+func (m *model) showCanvas() {
+	art := m.canvas.art()
+	m.v.ShowArt(&art)
+	m.v.SetCursor(m.canvas.x, m.canvas.y)
+}
+
+// dropCanvas throws the edits away.
+func (m *model) dropCanvas() {
+	m.canvas, m.editing = nil, false
+	m.v.HideCursor()
+}
+
+// updateCanvas edits the canvas: arrows move the cursor, shift+arrows nudge
+// the art, typing stamps characters and esc stops, keeping the edits.
+func (m model) updateCanvas(msg tea.KeyMsg) (model, tea.Cmd) {
+	c := m.canvas
+	moves := map[string][2]int{"up": {0, -1}, "down": {0, 1}, "left": {-1, 0}, "right": {1, 0}}
+	key := msg.String()
+	m.status = ""
+	switch {
+	case key == "esc":
+		m.editing = false
+		m.v.HideCursor()
+		m.status = "canvas kept (:canvas save FILE, :canvas off)"
+		return m, nil
+	case key == "ctrl+c":
+		return m, tea.Quit
+	case moves[key] != [2]int{}:
+		d := moves[key]
+		c.move(d[0], d[1])
+	case strings.HasPrefix(key, "shift+") && moves[strings.TrimPrefix(key, "shift+")] != [2]int{}:
+		d := moves[strings.TrimPrefix(key, "shift+")]
+		if !c.nudge(d[0], d[1]) {
+			m.status = "canvas: no room to nudge that way"
+		}
+	case key == "enter":
+		c.x = 0
+		c.move(0, 1)
+	case key == "backspace":
+		c.move(-1, 0)
+		c.erase()
+	case key == "delete":
+		c.erase()
+	case msg.Type == tea.KeySpace:
+		c.stamp(' ')
+	case msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && lipgloss.Width(string(msg.Runes)) == 1:
+		c.stamp(msg.Runes[0])
+	}
+	m.showCanvas()
+	return m, nil
+}
+
+// canvasStatus is the status line while editing.
+func (m model) canvasStatus() string {
+	return fmt.Sprintf("edit %d,%d  arrows move · shift+arrows nudge · type to stamp · del/backspace erase · esc done",
+		m.canvas.x, m.canvas.y)
+}
+
+func cmdCanvas(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 1 && args[0] == "off":
+		m.showPicture()
+		return "canvas off", nil
+	case len(args) >= 2 && args[0] == "save":
+		if m.public {
+			return "canvas: saving is not available in remote sessions", nil
+		}
+		if m.canvas == nil {
+			return "canvas: nothing edited yet (ctrl+e to start)", nil
+		}
+		path := strings.Join(args[1:], " ")
+		data := []byte(m.canvas.text())
+		if strings.HasSuffix(strings.ToLower(path), ".ans") {
+			data = m.canvas.art().Colorize(m.settings().options(0)).ANS(nil)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return "canvas: " + err.Error(), nil
+		}
+		return "canvas saved to " + path, nil
+	}
+	return "usage: canvas save FILE | canvas off", nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/muesli/termenv"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Terminal capabilities (--caps)
+//------------------------------------------------------------------------------
+
+// termCaps is what a terminal can show, as far as its environment tells.
+// Nothing is queried from the terminal itself: a reply that arrives late
+// would end up as typed input.
+type termCaps struct {
+	term    string
+	color   termenv.Profile
+	unicode bool // block fills, box drawing
+	braille bool
+	sixel   bool
+	kitty   bool // kitty graphics protocol
+	iterm2  bool // OSC 1337 inline images
+	legacy  bool // legacy Windows console; see setLegacy
+}
+
+// poorGlyphs render badly, or as boxes, in legacy Windows consoles.
+const poorGlyphs = "▓·"
+
+// detectCaps reads the capabilities from an environment, such as os.Getenv
+// or an SSH session's; color is the profile lipgloss picked for the output.
+func detectCaps(getenv func(string) string, color termenv.Profile) termCaps {
+	c := termCaps{term: getenv("TERM"), color: color}
+	program := getenv("TERM_PROGRAM")
+
+	locale := getenv("LC_ALL")
+	if locale == "" {
+		locale = getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = getenv("LANG")
+	}
+	if locale = strings.ToLower(locale); locale != "" {
+		c.unicode = strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	} else {
+		c.unicode = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+	}
+	switch c.term {
+	case "dumb", "vt100", "vt102", "vt220", "ansi":
+		c.unicode = false
+	}
+	c.braille = c.unicode && c.term != "linux" // the console font has no braille
+
+	switch {
+	case strings.Contains(c.term, "sixel"), c.term == "foot", c.term == "mlterm",
+		program == "WezTerm", program == "contour", program == "iTerm.app", program == "mintty":
+		c.sixel = true
+	}
+	switch {
+	case strings.Contains(c.term, "kitty"), strings.Contains(c.term, "ghostty"),
+		getenv("KITTY_WINDOW_ID") != "", program == "ghostty", program == "WezTerm":
+		c.kitty = true
+	}
+	c.iterm2 = program == "iTerm.app" || program == "WezTerm"
+	return c
+}
+
+// setLegacy limits c to what a legacy Windows console draws well: 16
+// colors, nothing from poorGlyphs and no braille.
+func (c *termCaps) setLegacy() {
+	c.legacy = true
+	c.braille = false
+	c.color = max(c.color, termenv.ANSI) // profiles grow poorer upwards
+}
+
+// fits reports whether the terminal can draw every character mode uses.
+func (c termCaps) fits(mode banner.RenderMode) bool {
+	for _, r := range mode.Cell(0, 0, '#', banner.Color{R: 255}) {
+		switch {
+		case r >= 0x2800 && r <= 0x28ff:
+			if !c.braille {
+				return false
+			}
+		case r >= 0x80:
+			if !c.unicode || c.legacy && strings.ContainsRune(poorGlyphs, r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// adjust swaps a render mode the terminal can't draw for glyph mode and
+// says so; colors need no help, since lipgloss degrades them to the
+// profile.
+func (c termCaps) adjust(s *settings) string {
+	mode, err := banner.ParseMode(s.Mode)
+	if err != nil || c.fits(mode) {
+		return ""
+	}
+	s.Mode = banner.ModeGlyph.Name()
+	return fmt.Sprintf("mode %s needs a Unicode terminal; showing %s", mode.Name(), s.Mode)
+}
+
+// report prints the capabilities for --caps.
+func (c termCaps) report(w io.Writer) {
+	yes := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	var modes []string
+	for _, m := range banner.Modes() {
+		if c.fits(m) {
+			modes = append(modes, m.Name())
+		}
+	}
+	term := c.term
+	if term == "" {
+		term = "(unset)"
+	}
+	fmt.Fprintf(w, "terminal:  %s\n", term)
+	fmt.Fprintf(w, "colors:    %s\n", c.color.Name())
+	fmt.Fprintf(w, "unicode:   %s\n", yes(c.unicode))
+	fmt.Fprintf(w, "braille:   %s\n", yes(c.braille))
+	fmt.Fprintf(w, "sixel:     %s\n", yes(c.sixel))
+	fmt.Fprintf(w, "kitty:     %s\n", yes(c.kitty))
+	fmt.Fprintf(w, "iterm2:    %s\n", yes(c.iterm2))
+	fmt.Fprintf(w, "legacy:    %s\n", yes(c.legacy))
+	fmt.Fprintf(w, "modes:     %s\n", strings.Join(modes, " "))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Clock feed (--clock[=FORMAT])
+//------------------------------------------------------------------------------
+
+const defaultClockFormat = "%H:%M:%S"
+
+// clockFeed shows the local time in a strftime format.
+type clockFeed struct{ format string }
+
+func (clockFeed) name() string { return "clock" }
+
+func (c clockFeed) text(now time.Time) (string, error) { return strftime(c.format, now) }
+
+func (clockFeed) next(now time.Time) time.Time { return now.Truncate(time.Second).Add(time.Second) }
+
+// strftimeLayouts maps strftime conversions to Go layouts.
+var strftimeLayouts = map[byte]string{
+	'a': "Mon", 'A': "Monday", 'b': "Jan", 'h': "Jan", 'B': "January",
+	'd': "02", 'e': "_2", 'm': "01", 'y': "06", 'Y': "2006",
+	'H': "15", 'I': "03", 'l': "3", 'M': "04", 'S': "05", 'p': "PM",
+	'Z': "MST", 'z': "-0700",
+	'D': "01/02/06", 'F': "2006-01-02", 'R': "15:04", 'T': "15:04:05", 'r': "03:04:05 PM",
+}
+
+// strftime formats t with the common strftime conversions, plus %k (hour,
+// space padded), %j (day of the year) and %%. Other text is copied.
+func strftime(format string, t time.Time) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		if i++; i == len(format) {
+			return "", fmt.Errorf("format %q ends in %%", format)
+		}
+		switch c := format[i]; c {
+		case '%':
+			b.WriteByte('%')
+		case 'k':
+			fmt.Fprintf(&b, "%2d", t.Hour())
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		default:
+			layout, ok := strftimeLayouts[c]
+			if !ok {
+				return "", fmt.Errorf("format %q: unknown conversion %%%c", format, c)
+			}
+			b.WriteString(t.Format(layout))
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Collages of banners (--collage LAYOUT --panel SETTINGS ...)
+//------------------------------------------------------------------------------
+
+// panelList collects --panel flags, each a query string of settings such
+// as "text=CPU&font=doom".
+type panelList []string
+
+func (p *panelList) String() string { return strings.Join(*p, " ") }
+
+func (p *panelList) Set(v string) error {
+	if _, err := url.ParseQuery(v); err != nil {
+		return err
+	}
+	*p = append(*p, v)
+	return nil
+}
+
+// collageGrid is the columns and rows of a layout: "rows" stacks the
+// panels, "columns" sets them side by side and "CxR" is a grid.
+func collageGrid(layout string, panels int) (cols, rows int, err error) {
+	switch layout {
+	case "rows":
+		return 1, panels, nil
+	case "columns":
+		return panels, 1, nil
+	}
+	c, r, ok := strings.Cut(layout, "x")
+	cols, errC := strconv.Atoi(c)
+	rows, errR := strconv.Atoi(r)
+	if !ok || errC != nil || errR != nil || cols < 1 || rows < 1 {
+		return 0, 0, fmt.Errorf("unknown collage layout %q (want rows, columns or a grid such as 2x2)", layout)
+	}
+	if cols*rows < panels {
+		return 0, 0, fmt.Errorf("collage %s has room for %d panels, not %d", layout, cols*rows, panels)
+	}
+	return cols, rows, nil
+}
+
+// collage is the TUI for --collage: banners laid out in a grid, each with
+// its own settings and animation. q quits.
+type collage struct {
+	w, h       int
+	cols, rows int
+	panels     []viewer.Model
+}
+
+// newCollage builds the panels, each starting from base and then its
+// --panel settings.
+func newCollage(c cliFlags, base settings) (collage, error) {
+	if len(c.panels) == 0 {
+		return collage{}, errors.New("collage: no --panel given")
+	}
+	layout := c.collage
+	if layout == "" {
+		layout = "rows"
+	}
+	cols, rows, err := collageGrid(layout, len(c.panels))
+	if err != nil {
+		return collage{}, err
+	}
+	out := collage{cols: cols, rows: rows}
+	for i, p := range c.panels {
+		q, _ := url.ParseQuery(p) // checked by Set
+		s := base
+		if err := applyLayer(&s, fmt.Sprintf("panel %d", i+1), func(key string) (string, bool) {
+			v, ok := q[key]
+			if !ok || len(v) == 0 {
+				return "", false
+			}
+			return v[0], true
+		}); err != nil {
+			return collage{}, err
+		}
+		if err := s.validate(); err != nil {
+			return collage{}, fmt.Errorf("panel %d: %w", i+1, err)
+		}
+		m := model{v: viewer.New()}
+		_ = m.applySettings(s) // Init starts the animation
+		out.panels = append(out.panels, m.v)
+	}
+	return out, nil
+}
+
+func runCollage(c cliFlags, base settings) error {
+	m, err := newCollage(c, base)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m collage) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range m.panels {
+		cmds = append(cmds, p.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update quits on q and passes everything but keys to the panels, whose
+// ticks carry their own IDs.
+func (m collage) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w, m.h = msg.Width, msg.Height
+		for i := range m.panels {
+			m.panels[i].SetWidth(m.w / m.cols)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	for i := range m.panels {
+		v, cmd := m.panels[i].Update(msg)
+		m.panels[i] = v.(viewer.Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// View centers each banner in its share of the terminal. Wide art wraps
+// to its cell; art still too tall is cut off.
+func (m collage) View() string {
+	if m.w == 0 || m.h == 0 {
+		return "\n  loading…"
+	}
+	r := lipgloss.DefaultRenderer()
+	cw, ch := m.w/m.cols, m.h/m.rows
+	var rows []string
+	for y := range m.rows {
+		var cells []string
+		for x := range m.cols {
+			art := ""
+			if i := y*m.cols + x; i < len(m.panels) {
+				art = r.NewStyle().MaxWidth(cw).MaxHeight(ch).Render(m.panels[i].PreviewView())
+			}
+			cells = append(cells, r.Place(cw, ch, lipgloss.Center, lipgloss.Center, art))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Command prompt (":")
+//------------------------------------------------------------------------------
+
+// commandFunc runs a ":" command. The returned string is shown in the status
+// line.
+type commandFunc func(m *model, args []string) (string, tea.Cmd)
+
+var commands = map[string]commandFunc{
+	"canvas": cmdCanvas,
+	"color":  cmdColor,
+	"effect": cmdEffect,
+	"image":  cmdImage,
+	"reset":  cmdReset,
+	"share":  cmdShare,
+	"sort":   cmdSort,
+	"theme":  cmdTheme,
+	"view":   cmdView,
+	"words":  cmdWords,
+}
+
+func newCommandLine() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = ":"
+	ti.CharLimit = 256
+	ti.Width = 40
+	return ti
+}
+
+func (m *model) openCommandLine() tea.Cmd {
+	m.cmdActive = true
+	m.cmdline.SetValue("")
+	return m.cmdline.Focus()
+}
+
+func (m *model) closeCommandLine() {
+	m.cmdActive = false
+	m.cmdline.Blur()
+}
+
+// updateCommandLine handles input while the prompt is open.
+func (m model) updateCommandLine(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, appKeys.Cancel):
+		m.closeCommandLine()
+		m.quitAfterSave = false
+		return m, nil
+	case key.Matches(msg, appKeys.Run):
+		line := m.cmdline.Value()
+		m.closeCommandLine()
+		var cmd tea.Cmd
+		m.status, cmd = m.runCommand(line)
+		m.quitAfterSave = false // the one command it was for has run
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.cmdline, cmd = m.cmdline.Update(msg)
+	return m, cmd
+}
+
+func (m *model) runCommand(line string) (string, tea.Cmd) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	fn, ok := commands[fields[0]]
+	if !ok {
+		return "unknown command: " + fields[0] + " (try " + strings.Join(commandNames(), ", ") + ")", nil
+	}
+	return fn(m, fields[1:])
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cmdShare(m *model, _ []string) (string, tea.Cmd) {
+	return "share: " + encodeSettings(m.settings()), nil
+}
+
+// cmdSort sorts the font list; sorting again by height or width measures
+// the text as it is now.
+func cmdSort(m *model, args []string) (string, tea.Cmd) {
+	if len(args) != 1 {
+		return "usage: sort " + strings.Join(viewer.FontOrders, "|") + " (now " + m.v.FontOrder() + ")", nil
+	}
+	if err := m.v.SetFontOrder(args[0]); err != nil {
+		return "sort: " + err.Error(), nil
+	}
+	return "fonts sorted by " + args[0], nil
+}
+
+func cmdWords(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 0:
+		if len(m.v.WordColors()) == 0 {
+			return "usage: words WORD=COLOR, ... | words off", nil
+		}
+		return "words: " + banner.FormatWordColors(m.v.WordColors()), nil
+	case len(args) == 1 && args[0] == "off":
+		m.v.SetWordColors(nil)
+		return "word colors off", nil
+	}
+	words, err := banner.ParseWordColors(strings.Join(args, " "))
+	if err != nil {
+		return "words: " + err.Error(), nil
+	}
+	m.v.SetWordColors(words)
+	return "words: " + banner.FormatWordColors(words), nil
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"glamdm/pkg/banner"
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Startup configuration
+//------------------------------------------------------------------------------
+
+// Settings are resolved in layers, later layers winning:
+//   defaults < user config < project profile < theme < environment < CLI flags
+//
+// Every layer uses the same keys: text, font, gradient ("#start,#end"),
+// start, end, stops, mode, animate, step, interval, normalize, words and sort.
+
+const (
+	envPrefix      = "ASCII_VIEWER_"
+	configDirName  = "ascii-viewer"
+	configFileName = "config.toml"
+	profileName    = ".ascii-viewer.toml"
+)
+
+var settingKeys = []string{"text", "font", "gradient", "start", "end", "stops", "mode", "animate", "step", "interval", "normalize", "words", "sort"}
+
+var settingUsage = map[string]string{
+	"text":      "banner text",
+	"font":      "FIGlet font name",
+	"gradient":  "gradient as \"#start,#end\"",
+	"start":     "gradient start color (hex)",
+	"end":       "gradient end color (hex)",
+	"stops":     "gradient colors between start and end, such as \"#ff0000@50, #ffff00@75\"",
+	"mode":      "render mode: block, glyph, light or dots",
+	"animate":   "cycle hues",
+	"step":      "hue degrees per tick (0.5-30)",
+	"interval":  "animation tick interval (e.g. 60ms)",
+	"normalize": "Unicode form for the text: nfc, nfd or strip (drop accents)",
+	"words":     "colors for single words, such as \"deploy=green, FAILED=red\"",
+	"sort":      "font list order: default, name, or height or width of the text",
+}
+
+// applyLayer overlays the values lookup knows about onto s. src names the
+// layer in error messages.
+func applyLayer(s *settings, src string, lookup func(key string) (string, bool)) error {
+	for _, key := range settingKeys {
+		v, ok := lookup(key)
+		v = strings.TrimSpace(v)
+		if !ok || v == "" {
+			continue
+		}
+		if err := setSetting(s, key, v); err != nil {
+			return fmt.Errorf("%s: %s: %w", src, key, err)
+		}
+	}
+	return nil
+}
+
+func setSetting(s *settings, key, v string) error {
+	switch key {
+	case "text":
+		s.Text = v
+	case "font":
+		s.Font = v
+	case "gradient":
+		start, end, err := parseGradient(v)
+		if err != nil {
+			return err
+		}
+		s.Start, s.End = start, end
+	case "start":
+		s.Start = v
+	case "end":
+		s.End = v
+	case "stops":
+		if _, err := banner.ParseStops(v); err != nil {
+			return err
+		}
+		s.Stops = v
+	case "mode":
+		mode, err := banner.ParseMode(v)
+		if err != nil {
+			return err
+		}
+		s.Mode = mode.Name()
+	case "animate":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		s.Animate = b
+	case "step":
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		s.StepDeg = f
+	case "interval":
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		s.Interval = d
+	case "normalize":
+		n, err := banner.ParseNormalization(v)
+		if err != nil {
+			return err
+		}
+		s.Normalize = n.String()
+	case "words":
+		if _, err := banner.ParseWordColors(v); err != nil {
+			return err
+		}
+		s.Words = v
+	case "sort":
+		if !slices.Contains(viewer.FontOrders, v) {
+			return fmt.Errorf("want %s", strings.Join(viewer.FontOrders, ", "))
+		}
+		s.Sort = v
+	default:
+		return fmt.Errorf("unknown setting")
+	}
+	return nil
+}
+
+// envLookup maps a setting key to its ASCII_VIEWER_* variable.
+func envLookup(key string) (string, bool) {
+	return os.LookupEnv(envPrefix + strings.ToUpper(key))
+}
+
+// envFlagName is the variable standing in for flag name: ASCII_VIEWER_ and
+// the name in upper case, dashes as underscores.
+func envFlagName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// envFlags sets each of fs's flags not given on the command line from its
+// ASCII_VIEWER_* variable, so a container can be configured from its
+// environment alone. Settings are left to the environment layer, and a
+// subcommand's copies of the top-level flags to main, which applied them.
+func envFlags(fs *flag.FlagSet) error {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] || knownSetting(f.Name) {
+			return
+		}
+		if fs != flag.CommandLine && flag.CommandLine.Lookup(f.Name) != nil {
+			return
+		}
+		if v, ok := os.LookupEnv(envFlagName(f.Name)); ok && v != "" {
+			if serr := fs.Set(f.Name, v); serr != nil {
+				err = fmt.Errorf("$%s: %w", envFlagName(f.Name), serr)
+			}
+		}
+	})
+	return err
+}
+
+// applyFile overlays a TOML file onto s.
+func applyFile(s *settings, path string) error {
+	var values map[string]any
+	if _, err := toml.DecodeFile(path, &values); err != nil {
+		return err
+	}
+	if undecoded := unknownKeys(values); len(undecoded) > 0 {
+		return fmt.Errorf("%s: unknown keys %s", path, strings.Join(undecoded, ", "))
+	}
+	return applyLayer(s, path, func(key string) (string, bool) {
+		v, ok := values[key]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	})
+}
+
+func unknownKeys(values map[string]any) []string {
+	var out []string
+	for k := range values {
+		if !knownSetting(k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func knownSetting(key string) bool {
+	for _, k := range settingKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// userConfigPath is the per-user config file, or "" if there is no config dir.
+func userConfigPath() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, configFileName)
+	}
+	return ""
+}
+
+// findProfile walks up from dir looking for a project profile, the way
+// editors look for .editorconfig.
+func findProfile(dir string) string {
+	for {
+		p := filepath.Join(dir, profileName)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// configFiles lists the config files that exist, lowest priority first;
+// none with --no-config.
+func configFiles() []string {
+	var files []string
+	if noConfig {
+		return nil
+	}
+	if p := userConfigPath(); p != "" {
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, p)
+		}
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if p := findProfile(wd); p != "" {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// parseGradient splits "#start,#end" into its two colors.
+func parseGradient(v string) (start, end string, err error) {
+	parts := strings.Split(v, ",")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("want \"#start,#end\", got %q", v)
+	}
+	start, end = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if _, ok := banner.ParseHex(start); !ok {
+		return "", "", fmt.Errorf("invalid color %q", start)
+	}
+	if _, ok := banner.ParseHex(end); !ok {
+		return "", "", fmt.Errorf("invalid color %q", end)
+	}
+	return start, end, nil
+}
+
+// cliFlags holds the startup flags that feed loadSettings.
+type cliFlags struct {
+	from          string            // :share token
+	theme         string            // saved theme name
+	effect        string            // Lua effect name
+	color         string            // color formula
+	control       string            // control socket path
+	mqtt          string            // broker/topic to take text from
+	ambiguousWide bool              // East Asian ambiguous-width runes take two cells
+	reduceMotion  bool              // no flashing
+	compat        string            // legacy console mode: auto, on or off
+	clock         string            // strftime format of the clock feed
+	countdown     time.Duration     // length of the countdown feed
+	stopwatch     bool              // show the stopwatch feed
+	finish        string            // what a countdown does at zero
+	calendar      string            // strftime format of the calendar feed
+	month         bool              // show the month under the calendar feed
+	sysinfo       string            // fields of the sysinfo feed
+	weather       string            // location of the weather feed
+	weatherKey    string            // OpenWeather API key
+	weatherEvery  time.Duration     // how often the weather feed refetches
+	git           string            // repository of the git feed
+	gitEvery      time.Duration     // how often the git feed rechecks the work tree
+	art           string            // pre-rendered art colored instead of the text
+	play          string            // hand-made animation played instead of the text
+	playSep       string            // line between frames in a --play file
+	playFPS       float64           // frames per second of --play
+	image         string            // picture shown instead of the text
+	imageStyle    string            // how the picture is drawn
+	view          string            // .ans or .nfo file opened at startup
+	collage       string            // layout of the --panel banners
+	panels        panelList         // settings of each collage banner
+	backdrop      string            // picture behind render and overlay banners
+	backdropDim   float64           // how much the backdrop is darkened
+	backdropStyle string            // how the backdrop is drawn
+	duration      time.Duration     // exit after this long
+	loops         int               // exit after this many animation loops
+	values        map[string]string // per-setting flags that were given, by key
+}
+
+// settingFlag records a per-setting flag only when it is actually passed, so
+// unset flags don't mask lower layers.
+type settingFlag struct {
+	key    string
+	values map[string]string
+}
+
+func (f settingFlag) String() string { return f.values[f.key] }
+
+func (f settingFlag) Set(v string) error {
+	var s settings
+	if err := setSetting(&s, f.key, v); err != nil {
+		return err
+	}
+	f.values[f.key] = v
+	return nil
+}
+
+// IsBoolFlag lets --animate stand alone.
+func (f settingFlag) IsBoolFlag() bool { return f.key == "animate" }
+
+// legacyConsole reports whether to work around a legacy Windows console:
+// --compat on, or --compat auto on Windows outside Windows Terminal.
+func (c cliFlags) legacyConsole() bool {
+	switch c.compat {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == ""
+}
+
+// register adds --from, --theme and a flag for every setting key to fs.
+// Several flag sets may share one cliFlags.
+func (c *cliFlags) register(fs *flag.FlagSet) {
+	if c.values == nil {
+		c.values = map[string]string{}
+		c.ambiguousWide = banner.LocaleAmbiguousWide()
+		c.reduceMotion, _ = strconv.ParseBool(os.Getenv(envPrefix + "REDUCE_MOTION"))
+		c.compat = "auto"
+		c.finish = "flash"
+		c.weatherKey = os.Getenv(envPrefix + "WEATHER_KEY")
+		c.weatherEvery = defaultWeatherInterval
+		c.gitEvery = defaultGitInterval
+		c.playSep = defaultPlaySep
+		c.playFPS = defaultPlayFPS
+		c.imageStyle = "ascii"
+		c.backdropDim = defaultBackdropDim
+		c.backdropStyle = "ascii"
+	}
+	fs.StringVar(&c.from, "from", c.from, "load settings from a :share string")
+	fs.StringVar(&c.theme, "theme", c.theme, "apply a saved theme")
+	fs.StringVar(&c.control, "control", c.control, "listen for key=value updates on this unix socket")
+	fs.StringVar(&c.mqtt, "mqtt", c.mqtt, "show payloads from an MQTT topic, as broker/topic")
+	fs.StringVar(&c.color, "color", c.color, "color formula, e.g. 'hsv(360*x/w + 40*t, 0.8, 1)'")
+	fs.StringVar(&c.effect, "effect", c.effect, "run a built-in effect, or a Lua effect from the effects directory")
+	fs.StringVar(&c.compat, "compat", c.compat, "legacy Windows console mode: auto, on or off")
+	fs.Var(optionalValue{&c.clock, defaultClockFormat}, "clock", "show the time as the banner; --clock=FORMAT takes a strftime format (default "+defaultClockFormat+")")
+	fs.DurationVar(&c.countdown, "countdown", c.countdown, "count down this long in big digits, e.g. 10m")
+	fs.BoolVar(&c.stopwatch, "stopwatch", c.stopwatch, "show a stopwatch in big digits")
+	fs.StringVar(&c.finish, "finish", c.finish, "when the countdown ends: flash, color=#RRGGBB or run=COMMAND")
+	fs.Var(optionalValue{&c.calendar, defaultCalendarFormat}, "calendar", "show today's date as the banner; --calendar=FORMAT takes a strftime format (default "+defaultCalendarFormat+")")
+	fs.BoolVar(&c.month, "month", c.month, "with --calendar, show this month's calendar under the date")
+	fs.Var(optionalValue{&c.sysinfo, defaultSysinfoFields}, "sysinfo", "cycle through system info as the banner; --sysinfo=FIELDS picks from "+defaultSysinfoFields)
+	fs.StringVar(&c.weather, "weather", c.weather, "show the weather at this location as the banner, from wttr.in")
+	fs.StringVar(&c.weatherKey, "weather-key", c.weatherKey, "OpenWeather API key to use instead of wttr.in (default $"+envPrefix+"WEATHER_KEY)")
+	fs.DurationVar(&c.weatherEvery, "weather-interval", c.weatherEvery, "how often to refetch the weather, at least 1m")
+	fs.Var(optionalValue{&c.git, "."}, "git", "show the current git branch as the banner, red when dirty; --git=DIR for another repository")
+	fs.DurationVar(&c.gitEvery, "git-interval", c.gitEvery, "how often --git rechecks the work tree for changes")
+	fs.StringVar(&c.art, "art", c.art, "color this file of pre-rendered ASCII art (- for stdin) instead of composing the text")
+	fs.StringVar(&c.play, "play", c.play, "play a hand-made animation instead of the text: a directory of frame files, or one file of frames")
+	fs.StringVar(&c.playSep, "play-sep", c.playSep, "the line between frames in a --play file")
+	fs.Float64Var(&c.playFPS, "play-fps", c.playFPS, "frames per second of --play")
+	fs.StringVar(&c.image, "image", c.image, "show this PNG, JPEG or GIF as ASCII art instead of the text")
+	fs.StringVar(&c.imageStyle, "image-style", c.imageStyle, "how --image is drawn: ascii, or color for truecolor half blocks")
+	fs.StringVar(&c.collage, "collage", c.collage, "lay the --panel banners out as rows, columns or a grid such as 2x2 (default rows)")
+	fs.Var(&c.panels, "panel", "add a banner to the collage, its settings as a query string such as 'text=CPU&font=doom' (repeatable)")
+	fs.StringVar(&c.view, "view", c.view, "open this .ans or .nfo file in the ANSI art viewer")
+	fs.StringVar(&c.backdrop, "backdrop", c.backdrop, "draw render exports and the serve overlay over this PNG, JPEG or GIF")
+	fs.Float64Var(&c.backdropDim, "backdrop-dim", c.backdropDim, "darken the backdrop by 0 (not at all) to 1 (black)")
+	fs.StringVar(&c.backdropStyle, "backdrop-style", c.backdropStyle, "how --backdrop is drawn: ascii, or color for solid blocks")
+	fs.DurationVar(&c.duration, "duration", c.duration, "exit after this long, e.g. 10s, as demo scripts and recordings want; render --stream stops too")
+	fs.IntVar(&c.loops, "loops", c.loops, "exit after this many animation loops: hue cycles, or passes through --play; render --stream stops too")
+	fs.BoolVar(&c.reduceMotion, "reduce-motion", c.reduceMotion, "never flash: the flash effect holds still and countdowns end without flashing (default from $"+envPrefix+"REDUCE_MOTION)")
+	fs.BoolVar(&c.ambiguousWide, "ambiguous-wide", c.ambiguousWide, "treat ambiguous-width runes such as █ ▓ · as two cells wide (default from the locale)")
+	for _, key := range settingKeys {
+		fs.Var(settingFlag{key, c.values}, key, settingUsage[key])
+	}
+}
+
+// loadSettings resolves the startup settings from every layer.
+func loadSettings(f cliFlags) (settings, error) {
+	banner.SetAmbiguousWide(f.ambiguousWide) // process-wide, not a setting
+	reduceMotion = f.reduceMotion
+	switch f.compat {
+	case "auto", "on", "off":
+	default:
+		return settings{}, fmt.Errorf("--compat: want auto, on or off, got %q", f.compat)
+	}
+	s := defaultSettings()
+	for _, file := range configFiles() {
+		if err := applyFile(&s, file); err != nil {
+			return settings{}, err
+		}
+	}
+	if f.theme != "" {
+		if err := applyTheme(&s, f.theme); err != nil {
+			return settings{}, err
+		}
+	}
+	if err := applyLayer(&s, "environment", envLookup); err != nil {
+		return settings{}, err
+	}
+	if f.from != "" {
+		var err error
+		if s, err = decodeSettings(f.from); err != nil {
+			return settings{}, err
+		}
+	}
+	if err := applyLayer(&s, "flags", func(key string) (string, bool) {
+		v, ok := f.values[key]
+		return v, ok
+	}); err != nil {
+		return settings{}, err
+	}
+	if err := s.validate(); err != nil {
+		return settings{}, err
+	}
+	return s, nil
+}
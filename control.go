@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Control socket (--control PATH)
+//------------------------------------------------------------------------------
+
+// The control socket lets scripts change a running instance, one
+// "key=value" per line:
+//
+//	echo text=DEPLOYED | nc -U /tmp/banner.sock
+//
+// Keys are the setting keys plus theme=NAME and the short forms anim (for
+// animate) and on/off for booleans. Each line is answered with "ok" or
+// "error: ...". Unix sockets also work on Windows 10 and later.
+
+// controlMsg is one request from the socket; the reply goes back on reply.
+type controlMsg struct {
+	key, value string
+	reply      chan string
+}
+
+// listenControl serves the socket at path, forwarding requests to p.
+func listenControl(path string, p *tea.Program) (func(), error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("control socket %s: path exists and is not a socket", path)
+		}
+		// A socket left behind by a crash blocks Listen; refuse to take
+		// over one that is still answering.
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("control socket %s is in use", path)
+		}
+		os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveControl(conn, p)
+		}
+	}()
+	return func() { ln.Close() }, nil
+}
+
+func serveControl(conn net.Conn, p *tea.Program) {
+	defer recoverCrash()
+	defer conn.Close()
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			fmt.Fprintln(conn, "error: want key=value")
+			continue
+		}
+		reply := make(chan string, 1)
+		p.Send(controlMsg{key: strings.TrimSpace(key), value: strings.TrimSpace(value), reply: reply})
+		fmt.Fprintln(conn, <-reply)
+	}
+}
+
+// handleControl applies one socket request.
+func (m model) handleControl(msg controlMsg) (model, tea.Cmd) {
+	cmd, err := m.control(msg.key, msg.value)
+	if err != nil {
+		msg.reply <- "error: " + err.Error()
+		return m, nil
+	}
+	m.status = fmt.Sprintf("control: %s=%s", msg.key, msg.value)
+	msg.reply <- "ok"
+	return m, cmd
+}
+
+func (m *model) control(key, value string) (tea.Cmd, error) {
+	s := m.settings()
+	switch key {
+	case "theme":
+		if err := applyTheme(&s, value); err != nil {
+			return nil, err
+		}
+	case "anim":
+		key = "animate"
+		fallthrough
+	default:
+		if key == "animate" {
+			switch value {
+			case "on":
+				value = "true"
+			case "off":
+				value = "false"
+			}
+		}
+		if !knownSetting(key) {
+			return nil, errors.New("unknown key " + key)
+		}
+		if err := setSetting(&s, key, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return m.applySettings(s), nil
+}
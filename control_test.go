@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenControlKeepsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listenControl(path, nil); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("listenControl = %v, want a not-a-socket error", err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "keep me" {
+		t.Fatalf("file now %q, %v", b, err)
+	}
+}
+
+func TestListenControlStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("no unix sockets:", err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close() // leaves the socket file, as a crash would
+
+	stop, err := listenControl(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if _, err := listenControl(path, nil); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("second listenControl = %v, want in use", err)
+	}
+}
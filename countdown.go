@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Countdown and stopwatch feeds (--countdown DURATION, --stopwatch)
+//------------------------------------------------------------------------------
+
+// countdownFeed shows the time left until end, then stops at 0:00 and
+// runs the --finish action.
+type countdownFeed struct{ end time.Time }
+
+func (countdownFeed) name() string { return "countdown" }
+
+func (c countdownFeed) text(now time.Time) (string, error) {
+	return formatTimer(c.left(now)), nil
+}
+
+// next is when the shown seconds, rounded up, tick down.
+func (c countdownFeed) next(now time.Time) time.Time {
+	return c.end.Add(-(c.left(now) - time.Second))
+}
+
+func (c countdownFeed) over(now time.Time) bool { return !now.Before(c.end) }
+
+// left is the time remaining, rounded up to the second.
+func (c countdownFeed) left(now time.Time) time.Duration {
+	d := max(0, c.end.Sub(now))
+	return time.Duration(math.Ceil(d.Seconds())) * time.Second
+}
+
+// stopwatchFeed shows the time since start.
+type stopwatchFeed struct{ start time.Time }
+
+func (stopwatchFeed) name() string { return "stopwatch" }
+
+func (s stopwatchFeed) text(now time.Time) (string, error) {
+	return formatTimer(now.Sub(s.start).Truncate(time.Second)), nil
+}
+
+func (s stopwatchFeed) next(now time.Time) time.Time {
+	return s.start.Add(now.Sub(s.start).Truncate(time.Second) + time.Second)
+}
+
+// formatTimer shows d as M:SS, or H:MM:SS from an hour up.
+func formatTimer(d time.Duration) string {
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+//------------------------------------------------------------------------------
+// Finish actions (--finish)
+//------------------------------------------------------------------------------
+
+// finishAction is what happens when a countdown reaches zero: "flash",
+// "color=#RRGGBB" or "run=COMMAND".
+type finishAction struct {
+	kind  string // flash, color or run
+	value string
+}
+
+func parseFinish(spec string) (finishAction, error) {
+	kind, value, _ := strings.Cut(spec, "=")
+	switch kind {
+	case "flash":
+		return finishAction{kind: kind}, nil
+	case "color":
+		if _, ok := banner.ParseHex(value); !ok {
+			return finishAction{}, fmt.Errorf("--finish color: invalid color %q", value)
+		}
+		return finishAction{kind, value}, nil
+	case "run":
+		if value == "" {
+			return finishAction{}, fmt.Errorf("--finish run: missing command")
+		}
+		return finishAction{kind, value}, nil
+	}
+	return finishAction{}, fmt.Errorf("--finish: want flash, color=#RRGGBB or run=COMMAND, got %q", spec)
+}
+
+const (
+	flashInterval = 250 * time.Millisecond
+	flashTimes    = 6 // flashes, each one on and one off
+	flashColor    = "#FFFFFF"
+)
+
+// flashMsg turns the flash on or off; left counts the remaining toggles.
+type flashMsg struct{ left int }
+
+func flashAfter(left int) tea.Cmd {
+	return tea.Tick(flashInterval, func(time.Time) tea.Msg { return flashMsg{left} })
+}
+
+// finish runs the finish action.
+func (m model) finish() (model, tea.Cmd) {
+	m.status = m.feed.name() + ": done"
+	switch a := m.finishAction; a.kind {
+	case "color":
+		_ = m.v.SetColors(a.value, a.value)
+	case "run":
+		return m, runFinish(a.value)
+	default:
+		if reduceMotion {
+			return m, nil
+		}
+		m.flashFrom[0], m.flashFrom[1] = m.v.Colors()
+		return m.handleFlash(flashMsg{left: 2 * flashTimes})
+	}
+	return m, nil
+}
+
+// handleFlash alternates the gradient with flashColor, ending on the
+// original colors.
+func (m model) handleFlash(msg flashMsg) (model, tea.Cmd) {
+	if msg.left%2 == 0 {
+		_ = m.v.SetColors(flashColor, flashColor)
+	} else {
+		_ = m.v.SetColors(m.flashFrom[0], m.flashFrom[1])
+	}
+	if msg.left == 1 {
+		return m, nil
+	}
+	return m, flashAfter(msg.left - 1)
+}
+
+// runFinish runs command in the shell without waiting on the update loop,
+// then reports how it went in the status line.
+func runFinish(command string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		}
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return statusMsg(fmt.Sprintf("finish: %v: %s", err, strings.TrimSpace(string(out))))
+		}
+		return statusMsg("finish: ran " + command)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Crash handling
+//------------------------------------------------------------------------------
+
+// A panic anywhere in the TUI must not leave the terminal in raw mode on
+// the alternate screen. Bubble Tea's own panic catching is turned off in
+// favor of recoverCrash, which also covers our commands and goroutines and
+// keeps the stack trace out of the way in a file.
+
+// crashProgram is the running TUI, whose terminal a crash restores.
+var crashProgram atomic.Pointer[tea.Program]
+
+var crashOnce sync.Once
+
+// recoverCrash, deferred at the top of a goroutine, turns a panic into a
+// short report on a restored terminal and exits. Only the TUI uses it; the
+// servers must outlive a broken session.
+func recoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	crashOnce.Do(func() {
+		if p := crashProgram.Load(); p != nil {
+			_ = p.ReleaseTerminal()
+		}
+		fmt.Fprint(os.Stderr, "\x1b[0m\x1b[?25h\x1b[?1049l") // reset colors, show cursor, leave alt screen
+		fmt.Fprintf(os.Stderr, "ascii-viewer crashed: %v\n", r)
+		if path, err := writeCrashLog(r, stack); err == nil {
+			fmt.Fprintf(os.Stderr, "The stack trace is in %s; please attach it to a bug report.\n", path)
+		} else {
+			os.Stderr.Write(stack)
+		}
+	})
+	os.Exit(2)
+}
+
+func writeCrashLog(r any, stack []byte) (string, error) {
+	f, err := os.CreateTemp("", "ascii-viewer-crash-*.log")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "ascii-viewer crashed at %s: %v\n\n%s", time.Now().Format(time.RFC3339), r, stack)
+	return f.Name(), nil
+}
+
+// guard runs cmd, and any commands it batches, under recoverCrash.
+func guard(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil || crashProgram.Load() == nil { // servers keep Bubble Tea's handling
+		return cmd
+	}
+	return func() tea.Msg {
+		defer recoverCrash()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = guard(batch[i])
+			}
+		}
+		return msg
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Debug log (--debug FILE)
+//------------------------------------------------------------------------------
+
+// debugLog is the --debug log, or nil. It records what a bug report about a
+// rendering glitch needs: keys pressed, settings as they change, compose and
+// draw timings and font errors.
+var debugLog *log.Logger
+
+// openDebugLog appends the debug log to path until the returned func is
+// called. An empty path does nothing.
+func openDebugLog(path string) (func(), error) {
+	if path == "" {
+		return func() {}, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	debugLog = log.New(f, "", log.LstdFlags|log.Lmicroseconds)
+	debugLog.Printf("start: %q", os.Args)
+	return func() {
+		debugLog.Printf("exit")
+		f.Close()
+	}, nil
+}
+
+func debugf(format string, args ...any) {
+	if debugLog != nil {
+		debugLog.Printf(format, args...)
+	}
+}
+
+// debugMsg logs the messages worth a line: input and events from outside.
+// Ticks and other internal messages would drown them out.
+func debugMsg(msg tea.Msg) {
+	if debugLog == nil {
+		return
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		debugf("key %q", msg.String())
+	case tea.WindowSizeMsg:
+		debugf("resize %dx%d", msg.Width, msg.Height)
+	case viewer.SelectedMsg:
+		debugf("selected %s", msg.Font)
+	case controlMsg:
+		debugf("control %s %q", msg.key, msg.value)
+	case mqttMsg:
+		debugf("mqtt %q", msg.payload)
+	case signalMsg:
+		debugf("signal %d", int(msg))
+	case configReloadMsg:
+		debugf("config reload: %v", msg.err)
+	}
+}
+
+// debugState logs the settings when a message changed them.
+func debugState(before, after settings) {
+	if debugLog != nil && before != after {
+		debugf("state %+v", after)
+	}
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+	"glamdm/pkg/colorexpr"
+	"glamdm/pkg/luaeffect"
+)
+
+//------------------------------------------------------------------------------
+// Effects: built in, or Lua files in <config>/effects
+//------------------------------------------------------------------------------
+
+const (
+	effectsDirName = "effects"
+	effectExt      = ".lua"
+)
+
+// reduceMotion is --reduce-motion, for the effects to honor.
+var reduceMotion bool
+
+// builtinEffects are the effects that come with the app, by name, made
+// from their options. They win over scripts of the same name.
+var builtinEffects = map[string]func(o effectOptions) (banner.Effect, error){
+	"reflection": func(effectOptions) (banner.Effect, error) { return banner.NewReflection(), nil },
+	"snow":       func(effectOptions) (banner.Effect, error) { return banner.NewSnow(), nil },
+	"plasma": func(o effectOptions) (banner.Effect, error) {
+		intensity, err := o.float("intensity", 0.35)
+		if err != nil {
+			return nil, err
+		}
+		return banner.NewPlasma(o.take("palette", "ocean"), intensity)
+	},
+	"aberration": func(o effectOptions) (banner.Effect, error) {
+		offset, err := o.int("offset", 1)
+		if err != nil {
+			return nil, err
+		}
+		if offset < 1 || offset > 3 {
+			return nil, fmt.Errorf("offset %d out of range (1-3)", offset)
+		}
+		jitter, err := o.bool("jitter", true)
+		if err != nil {
+			return nil, err
+		}
+		return banner.NewAberration(offset, jitter), nil
+	},
+	"crt": func(o effectOptions) (banner.Effect, error) {
+		dim, err := o.float("dim", 0.35)
+		if err != nil {
+			return nil, err
+		}
+		if dim < 0 || dim > 1 {
+			return nil, fmt.Errorf("dim %g out of range (0-1)", dim)
+		}
+		jitter, err := o.bool("jitter", true)
+		if err != nil {
+			return nil, err
+		}
+		curve, err := o.bool("curve", false)
+		if err != nil {
+			return nil, err
+		}
+		return banner.NewCRT(dim, jitter, curve), nil
+	},
+	"cycle": func(o effectOptions) (banner.Effect, error) {
+		bands, err := o.int("bands", 8)
+		if err != nil {
+			return nil, err
+		}
+		every, err := o.int("every", 2)
+		if err != nil {
+			return nil, err
+		}
+		if bands < 2 || every < 1 {
+			return nil, fmt.Errorf("want at least 2 bands, stepping every 1 or more ticks")
+		}
+		return banner.NewPaletteCycle(bands, every), nil
+	},
+	"flash": func(o effectOptions) (banner.Effect, error) {
+		every, err := o.duration("every", time.Second)
+		if err != nil {
+			return nil, err
+		}
+		style := o.take("style", "brighten")
+		if style != "brighten" && style != "invert" {
+			return nil, fmt.Errorf("unknown style %q (want brighten or invert)", style)
+		}
+		f, err := banner.NewFlash(every, style == "invert")
+		if err != nil {
+			return nil, err
+		}
+		f.Still = reduceMotion
+		return f, nil
+	},
+	"grain": func(o effectOptions) (banner.Effect, error) {
+		amount, err := o.float("amount", 0.12)
+		if err != nil {
+			return nil, err
+		}
+		if amount < 0 || amount > 1 {
+			return nil, fmt.Errorf("amount %g out of range (0-1)", amount)
+		}
+		return banner.NewGrain(amount), nil
+	},
+	"glow": func(o effectOptions) (banner.Effect, error) {
+		radius, err := o.int("radius", 1)
+		if err != nil {
+			return nil, err
+		}
+		return banner.NewGlow(radius)
+	},
+}
+
+// effectOptions are the "key=value" words after a built-in effect's name,
+// as in "plasma palette=fire intensity=0.5". Each is taken as it is read;
+// any left over were not understood.
+type effectOptions map[string]string
+
+func parseEffectOptions(words []string) (effectOptions, error) {
+	o := effectOptions{}
+	for _, w := range words {
+		k, v, ok := strings.Cut(w, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("effect option %q is not key=value", w)
+		}
+		o[k] = v
+	}
+	return o, nil
+}
+
+func (o effectOptions) take(key, def string) string {
+	v, ok := o[key]
+	delete(o, key)
+	if !ok {
+		return def
+	}
+	return v
+}
+
+func (o effectOptions) int(key string, def int) (int, error) {
+	v := o.take(key, "")
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return n, nil
+}
+
+func (o effectOptions) bool(key string, def bool) (bool, error) {
+	v := o.take(key, "")
+	switch v {
+	case "":
+		return def, nil
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
+
+func (o effectOptions) duration(key string, def time.Duration) (time.Duration, error) {
+	v := o.take(key, "")
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return d, nil
+}
+
+func (o effectOptions) float(key string, def float64) (float64, error) {
+	v := o.take(key, "")
+	if v == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return f, nil
+}
+
+func effectsDir() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, effectsDirName)
+	}
+	return ""
+}
+
+// loadEffect makes a built-in effect from spec, its name and options, or
+// else compiles <config>/effects/<name>.lua.
+func loadEffect(spec string) (banner.Effect, error) {
+	words := strings.Fields(spec)
+	if len(words) == 0 {
+		return nil, errors.New("no effect named")
+	}
+	name := words[0]
+	if build, ok := builtinEffects[name]; ok {
+		o, err := parseEffectOptions(words[1:])
+		if err != nil {
+			return nil, err
+		}
+		e, err := build(o)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for k := range o {
+			return nil, fmt.Errorf("%s: unknown option %q", name, k)
+		}
+		return e, nil
+	}
+	if len(words) > 1 {
+		return nil, fmt.Errorf("effect %q takes no options", name)
+	}
+	if !validName(name) {
+		return nil, fmt.Errorf("invalid effect name %q", name)
+	}
+	dir := effectsDir()
+	if dir == "" {
+		return nil, errors.New("no config directory")
+	}
+	p := filepath.Join(dir, name+effectExt)
+	if _, err := os.Stat(p); err != nil {
+		return nil, fmt.Errorf("effect %q not found", name)
+	}
+	l, err := luaeffect.Load(p)
+	if err != nil {
+		return nil, err
+	}
+	l.SetLog(debugLog)
+	return l, nil
+}
+
+// effectNames lists the built-in effects and installed scripts.
+func effectNames() []string {
+	var names []string
+	for name := range builtinEffects {
+		names = append(names, name)
+	}
+	if dir := effectsDir(); dir != "" {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*"+effectExt))
+		for _, p := range paths {
+			if name := strings.TrimSuffix(filepath.Base(p), effectExt); builtinEffects[name] == nil {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// closeEffect releases a script's interpreter; built-in effects hold
+// nothing.
+func closeEffect(e banner.Effect) {
+	if l, ok := e.(*luaeffect.Effect); ok {
+		l.Close()
+	}
+}
+
+// setScript replaces the running effect; nil just removes it.
+func (m *model) setScript(e banner.Effect) {
+	if m.script != nil {
+		m.v.RemoveEffect(m.script)
+		closeEffect(m.script)
+	}
+	m.script = e
+	if e != nil {
+		m.v.AddEffect(e)
+	}
+}
+
+// checkScript drops a script that failed at runtime and reports why.
+func (m *model) checkScript() {
+	l, ok := m.script.(*luaeffect.Effect)
+	if !ok || l.Err() == nil {
+		return
+	}
+	m.status = "effect: " + l.Err().Error()
+	m.setScript(nil)
+}
+
+// cmdEffect implements ":effect NAME [OPTIONS]", ":effect off" and
+// ":effect" (list).
+func cmdEffect(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 0:
+		return "effects: " + strings.Join(effectNames(), ", "), nil
+	case len(args) == 1 && args[0] == "off":
+		m.setScript(nil)
+		return "effect off", nil
+	}
+	spec := strings.Join(args, " ")
+	e, err := loadEffect(spec)
+	if err != nil {
+		return "effect: " + err.Error(), nil
+	}
+	m.setScript(e)
+	return "effect " + spec, nil
+}
+
+//------------------------------------------------------------------------------
+// Color formulas (":color hsv(360*x/w + 40*t, 0.8, 1)")
+//------------------------------------------------------------------------------
+
+// setFormula replaces the color formula; nil just removes it.
+func (m *model) setFormula(e *colorexpr.Effect) {
+	if m.formula != nil {
+		m.v.RemoveEffect(m.formula)
+	}
+	m.formula = e
+	if e != nil {
+		m.v.AddEffect(e)
+	}
+}
+
+// cmdColor implements ":color FORMULA", ":color off" and ":color" (show).
+func cmdColor(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 0:
+		if m.formula == nil {
+			return "usage: color FORMULA | color off", nil
+		}
+		return "color: " + m.formula.String(), nil
+	case len(args) == 1 && args[0] == "off":
+		m.setFormula(nil)
+		return "color formula off", nil
+	}
+	e, err := colorexpr.Parse(strings.Join(args, " "))
+	if err != nil {
+		return "color: " + err.Error(), nil
+	}
+	m.setFormula(e)
+	return "color: " + e.String(), nil
+}
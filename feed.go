@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Text feeds (--clock, --countdown, --stopwatch, --calendar, --sysinfo,
+// --weather, --git)
+//------------------------------------------------------------------------------
+
+// A feed keeps the banner text up to date from something other than the
+// keyboard, such as the clock. Feeds are read off the update loop, so text
+// may block on I/O; only one read is in flight at a time.
+type feed interface {
+	name() string // for the status line
+	text(now time.Time) (string, error)
+	next(now time.Time) time.Time // when the text may change next
+}
+
+// A finite feed, such as a countdown, is read no more once over reports
+// true; the --finish action runs instead.
+type finite interface {
+	over(now time.Time) bool
+}
+
+// A captioned feed also has plain text to show under the banner.
+type captioned interface {
+	caption(now time.Time) string
+}
+
+// A tinted feed picks the gradient as it goes: tint returns the colors to
+// show, or zero values for the user's own.
+type tinted interface {
+	tint(now time.Time) [2]string
+}
+
+// feedMsg carries what a feed read.
+type feedMsg struct {
+	text    string
+	caption string
+	tint    [2]string
+	err     error
+	next    time.Time
+	done    bool // the feed is over
+}
+
+// newFeed builds the feed the flags ask for, or nil.
+func newFeed(c cliFlags) (feed, error) {
+	var feeds []feed
+	if c.clock != "" {
+		if _, err := strftime(c.clock, time.Now()); err != nil {
+			return nil, fmt.Errorf("--clock: %w", err)
+		}
+		feeds = append(feeds, clockFeed{format: c.clock})
+	}
+	if c.countdown > 0 {
+		feeds = append(feeds, countdownFeed{end: time.Now().Add(c.countdown)})
+	}
+	if c.stopwatch {
+		feeds = append(feeds, stopwatchFeed{start: time.Now()})
+	}
+	if c.calendar != "" {
+		if _, err := strftime(c.calendar, time.Now()); err != nil {
+			return nil, fmt.Errorf("--calendar: %w", err)
+		}
+		feeds = append(feeds, calendarFeed{format: c.calendar, month: c.month})
+	}
+	if c.sysinfo != "" {
+		f, err := newSysinfoFeed(c.sysinfo)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, f)
+	}
+	if c.weather != "" {
+		feeds = append(feeds, newWeatherFeed(c.weather, c.weatherKey, max(time.Minute, c.weatherEvery)))
+	}
+	if c.git != "" {
+		f, err := newGitFeed(c.git, c.gitEvery)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, f)
+	}
+	switch len(feeds) {
+	case 0:
+		return nil, nil
+	case 1:
+		return feeds[0], nil
+	}
+	names := make([]string, len(feeds))
+	for i, f := range feeds {
+		names[i] = "--" + f.name()
+	}
+	return nil, fmt.Errorf("%s: pick one", strings.Join(names, ", "))
+}
+
+// pollFeed reads f at time at.
+func pollFeed(f feed, at time.Time) tea.Cmd {
+	return tea.Tick(max(0, time.Until(at)), func(now time.Time) tea.Msg {
+		msg := feedMsg{next: f.next(now)}
+		msg.text, msg.err = f.text(now)
+		if c, ok := f.(captioned); ok {
+			msg.caption = c.caption(now)
+		}
+		if t, ok := f.(tinted); ok {
+			msg.tint = t.tint(now)
+		}
+		if fin, ok := f.(finite); ok {
+			msg.done = fin.over(now)
+		}
+		return msg
+	})
+}
+
+// handleFeed shows a feed's text and schedules its next read. A failed
+// read keeps the old text and says why in the status line.
+func (m model) handleFeed(msg feedMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = m.feed.name() + ": " + msg.err.Error()
+	} else if msg.text != m.v.Text() {
+		m.v.SetText(msg.text)
+	}
+	m.caption = msg.caption
+	m.setTint(msg.tint)
+	if msg.done {
+		return m.finish()
+	}
+	return m, pollFeed(m.feed, msg.next)
+}
+
+// setTint shows a tinted feed's colors, saving the user's gradient until
+// the tint is lifted.
+func (m *model) setTint(tint [2]string) {
+	switch {
+	case tint[0] != "":
+		if m.untinted[0] == "" {
+			m.untinted[0], m.untinted[1] = m.v.Colors()
+		}
+		if start, end := m.v.Colors(); start != tint[0] || end != tint[1] {
+			_ = m.v.SetColors(tint[0], tint[1])
+		}
+	case m.untinted[0] != "":
+		_ = m.v.SetColors(m.untinted[0], m.untinted[1])
+		m.untinted = [2]string{}
+	}
+}
+
+// optionalValue is a string flag that may also be given bare, as in
+// --clock, which sets it to def; a value then needs "=", as in
+// --clock=%H:%M.
+type optionalValue struct {
+	p   *string
+	def string
+}
+
+func (o optionalValue) String() string {
+	if o.p == nil {
+		return ""
+	}
+	return *o.p
+}
+
+func (o optionalValue) Set(v string) error {
+	switch v {
+	case "true":
+		v = o.def
+	case "false":
+		v = ""
+	}
+	*o.p = v
+	return nil
+}
+
+func (o optionalValue) IsBoolFlag() bool { return true }
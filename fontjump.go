@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Jump to a font ("g")
+//------------------------------------------------------------------------------
+
+// fontHints is how many matching fonts the prompt lists.
+const fontHints = 6
+
+func newFontPrompt() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "font: "
+	ti.CharLimit = 64
+	ti.Width = 24
+	return ti
+}
+
+func (m *model) openFontPrompt() tea.Cmd {
+	m.fontActive, m.fontTab = true, -1
+	m.fontPrompt.SetValue("")
+	return m.fontPrompt.Focus()
+}
+
+func (m *model) closeFontPrompt() {
+	m.fontActive = false
+	m.fontPrompt.Blur()
+}
+
+// fontMatches lists the fonts starting with prefix, ignoring case, then
+// those containing it.
+func fontMatches(fonts []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	var starts, contains []string
+	for _, f := range fonts {
+		switch name := strings.ToLower(f); {
+		case strings.HasPrefix(name, prefix):
+			starts = append(starts, f)
+		case strings.Contains(name, prefix):
+			contains = append(contains, f)
+		}
+	}
+	sort.Strings(starts)
+	sort.Strings(contains)
+	return append(starts, contains...)
+}
+
+// updateFontPrompt handles input while the prompt is open: tab completes
+// the name, pressing it again cycles through the matches, enter jumps to
+// the first match and a count followed by ] or [ skips that many fonts.
+func (m model) updateFontPrompt(msg tea.KeyMsg) (model, tea.Cmd) {
+	typed := m.fontPrompt.Value()
+	switch {
+	case key.Matches(msg, appKeys.Cancel):
+		m.closeFontPrompt()
+		return m, nil
+	case key.Matches(msg, appKeys.Skip):
+		n, err := strconv.Atoi(typed)
+		if err != nil || n <= 0 {
+			break // part of a name
+		}
+		if msg.String() == "[" {
+			n = -n
+		}
+		m.closeFontPrompt()
+		m.v.SkipFonts(n)
+		m.status = fmt.Sprintf("font %s (%+d)", m.v.Font(), n)
+		return m, nil
+	case key.Matches(msg, appKeys.Complete):
+		if m.fontTab < 0 {
+			m.fontBase = typed
+		}
+		matches := fontMatches(m.v.Fonts(), m.fontBase)
+		if len(matches) == 0 {
+			return m, nil
+		}
+		if msg.String() == "tab" {
+			m.fontTab++
+		} else {
+			m.fontTab += len(matches) - 1
+		}
+		m.fontTab %= len(matches)
+		m.fontPrompt.SetValue(matches[m.fontTab])
+		m.fontPrompt.CursorEnd()
+		return m, nil
+	case key.Matches(msg, appKeys.Jump):
+		m.closeFontPrompt()
+		if typed == "" {
+			return m, nil
+		}
+		matches := fontMatches(m.v.Fonts(), typed)
+		for _, f := range matches {
+			if strings.EqualFold(f, typed) {
+				matches = []string{f}
+			}
+		}
+		if len(matches) == 0 {
+			m.status = fmt.Sprintf("no font matching %q", typed)
+			return m, nil
+		}
+		_ = m.v.SetFont(matches[0])
+		m.status = "font " + matches[0]
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.fontPrompt, cmd = m.fontPrompt.Update(msg)
+	if m.fontPrompt.Value() != typed {
+		m.fontTab = -1 // typing starts completion over
+	}
+	return m, cmd
+}
+
+// fontPromptView is the prompt with the first few matches after it.
+func (m model) fontPromptView() string {
+	hint := ""
+	if typed := m.fontPrompt.Value(); typed != "" {
+		if _, err := strconv.Atoi(typed); err != nil {
+			matches := fontMatches(m.v.Fonts(), typed)
+			if len(matches) > fontHints {
+				matches = append(matches[:fontHints], fmt.Sprintf("+%d more", len(matches)-fontHints))
+			}
+			hint = strings.Join(matches, "  ")
+			if hint == "" {
+				hint = "no match"
+			}
+		}
+	}
+	return m.fontPrompt.View() + "  " + m.v.Renderer().NewStyle().Faint(true).Render(hint)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Fonts (built-in FIGlet fonts plus user .flf files)
+//------------------------------------------------------------------------------
+
+const (
+	fontsDirName = "fonts"
+	fontExt      = ".flf"
+)
+
+// noConfig hides the config dir and project profiles (--no-config), so
+// nothing is read from or written to them.
+var noConfig bool
+
+// configDir is ascii-viewer's directory under the user config dir, or ""
+// with --no-config or no user config dir, as when HOME is unset.
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil || noConfig {
+		return ""
+	}
+	return filepath.Join(dir, configDirName)
+}
+
+func fontsDir() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, fontsDirName)
+	}
+	return ""
+}
+
+// loadCustomFonts registers every usable .flf file in dir, warning on
+// stderr about the rest. Files named after a built-in font are ignored.
+func loadCustomFonts(dir string) {
+	if dir == "" {
+		return
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*"+fontExt))
+	for _, p := range paths {
+		name := strings.TrimSuffix(filepath.Base(p), fontExt)
+		if err := banner.CheckFont(p); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping font %s: %v\n", name, err)
+			debugf("font %s: %v", p, err)
+			continue
+		}
+		banner.RegisterFont(name, p)
+	}
+}
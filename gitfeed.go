@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Git branch feed (--git[=DIR])
+//------------------------------------------------------------------------------
+
+const defaultGitInterval = 5 * time.Second
+
+// Gradient of a repository with changes or an operation in progress.
+var gitDirtyColors = [2]string{"#FF5F5F", "#FFAF00"}
+
+// gitFeed shows a repository's branch, turning red when the work tree is
+// dirty. HEAD and the index are watched like the config files, so
+// switching branches or staging shows at once; edits to files are picked
+// up every --git-interval.
+type gitFeed struct {
+	dir    string
+	gitDir string
+	every  time.Duration
+
+	mods    map[string]time.Time // of HEAD and the index when last checked
+	checked time.Time
+	last    gitState
+}
+
+// gitState is what one git status said.
+type gitState struct {
+	branch  string
+	caption string // operation in progress, changes, ahead/behind
+	dirty   bool
+	err     error
+}
+
+func newGitFeed(dir string, every time.Duration) (*gitFeed, error) {
+	out, err := gitOutput(dir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("--git: %w", err)
+	}
+	return &gitFeed{dir: dir, gitDir: out, every: every}, nil
+}
+
+func (*gitFeed) name() string { return "git" }
+
+func (g *gitFeed) text(now time.Time) (string, error) {
+	mods := modTimes([]string{filepath.Join(g.gitDir, "HEAD"), filepath.Join(g.gitDir, "index")})
+	if g.checked.IsZero() || !sameMods(g.mods, mods) || now.Sub(g.checked) >= g.every {
+		g.mods, g.checked = mods, now
+		g.last = g.status()
+	}
+	return g.last.branch, g.last.err
+}
+
+func (*gitFeed) next(now time.Time) time.Time { return now.Add(watchInterval) }
+
+func (g *gitFeed) caption(time.Time) string { return g.last.caption }
+
+func (g *gitFeed) tint(time.Time) [2]string {
+	if g.last.dirty {
+		return gitDirtyColors
+	}
+	return [2]string{}
+}
+
+// status runs git status and reads the branch line and change count.
+func (g *gitFeed) status() gitState {
+	out, err := gitOutput(g.dir, "status", "--porcelain=v1", "--branch")
+	if err != nil {
+		return gitState{err: err}
+	}
+	lines := strings.Split(out, "\n")
+	head, changes := strings.TrimPrefix(lines[0], "## "), len(lines)-1
+
+	op := g.operation()
+	st := gitState{dirty: changes > 0 || op != ""}
+	var notes []string
+	if op != "" {
+		notes = append(notes, op)
+	}
+	switch {
+	case strings.HasPrefix(head, "HEAD (no branch)"):
+		sha, err := gitOutput(g.dir, "rev-parse", "--short", "HEAD")
+		if err != nil {
+			return gitState{err: err}
+		}
+		st.branch = sha
+		notes = append(notes, "detached")
+	case strings.HasPrefix(head, "No commits yet on "):
+		st.branch = strings.TrimPrefix(head, "No commits yet on ")
+	default:
+		branch, track, _ := strings.Cut(head, " ")
+		branch, _, _ = strings.Cut(branch, "...")
+		st.branch = branch
+		if track != "" {
+			notes = append(notes, strings.Trim(track, "[]"))
+		}
+	}
+	if changes > 0 {
+		notes = append(notes, fmt.Sprintf("%d changed", changes))
+	}
+	st.caption = strings.Join(notes, ", ")
+	return st
+}
+
+// gitOperations are the files git leaves while an operation is under way.
+var gitOperations = []struct{ file, name string }{
+	{"rebase-merge", "rebasing"},
+	{"rebase-apply", "rebasing"},
+	{"MERGE_HEAD", "merging"},
+	{"CHERRY_PICK_HEAD", "cherry-picking"},
+	{"REVERT_HEAD", "reverting"},
+	{"BISECT_LOG", "bisecting"},
+}
+
+// operation names the operation in progress, or "".
+func (g *gitFeed) operation() string {
+	for _, op := range gitOperations {
+		if _, err := os.Stat(filepath.Join(g.gitDir, op.file)); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+// gitOutput runs git in dir and returns its trimmed output, or its error
+// message as the error.
+func gitOutput(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			msg, _, _ = strings.Cut(msg, "\n")
+			return "", errors.New(strings.TrimPrefix(msg, "fatal: "))
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Gradient stop editor (ctrl+g)
+//------------------------------------------------------------------------------
+
+const (
+	stopStep = 0.05 // how far shift+←/→ moves a stop
+	stopGap  = 0.01 // closest a moved stop comes to its neighbours
+)
+
+func newStopInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "color: "
+	ti.CharLimit = 7
+	ti.Width = 8
+	return ti
+}
+
+// gradientStops is the whole gradient as stops: the start at 0, the
+// viewer's stops, then the end at 1.
+func (m model) gradientStops() []banner.Stop {
+	start, end := m.v.Colors()
+	s, _ := banner.ParseHex(start)
+	e, _ := banner.ParseHex(end)
+	return append(append([]banner.Stop{{At: 0, Color: s}}, m.v.Stops()...), banner.Stop{At: 1, Color: e})
+}
+
+// setGradientStops is the inverse of gradientStops.
+func (m *model) setGradientStops(stops []banner.Stop) {
+	last := len(stops) - 1
+	_ = m.v.SetColors(stops[0].Color.Hex(), stops[last].Color.Hex())
+	m.v.SetStops(stops[1:last])
+}
+
+func (m *model) openGradient() tea.Cmd {
+	m.gradOpen = true
+	return m.selectStop(0)
+}
+
+func (m *model) closeGradient() {
+	m.gradOpen = false
+	m.gradInput.Blur()
+}
+
+// selectStop selects stop i of gradientStops and puts its color in the
+// input.
+func (m *model) selectStop(i int) tea.Cmd {
+	m.gradStop = i
+	m.gradInput.SetValue(m.gradientStops()[i].Color.Hex())
+	m.gradInput.CursorEnd()
+	return m.gradInput.Focus()
+}
+
+// updateGradient handles keys while the editor is open: tab selects a stop,
+// shift+←/→ moves it, + adds one after it and - removes it. Typing a valid
+// hex color recolors the selected stop as it is typed.
+func (m model) updateGradient(msg tea.KeyMsg) (model, tea.Cmd) {
+	stops := m.gradientStops()
+	i, last := m.gradStop, len(stops)-1
+	switch {
+	case key.Matches(msg, gradientKeys.Done):
+		m.closeGradient()
+		return m, nil
+	case key.Matches(msg, gradientKeys.Select):
+		if msg.String() == "tab" {
+			i++
+		} else {
+			i += last
+		}
+		return m, m.selectStop(i % len(stops))
+	case key.Matches(msg, gradientKeys.Move):
+		if i == 0 || i == last {
+			m.status = "the endpoints stay at 0% and 100%"
+			return m, nil
+		}
+		at := stops[i].At + stopStep
+		if msg.String() == "shift+left" {
+			at = stops[i].At - stopStep
+		}
+		at = math.Round(at/stopGap) * stopGap
+		stops[i].At = min(max(at, stops[i-1].At+stopGap), stops[i+1].At-stopGap)
+		m.setGradientStops(stops)
+		return m, nil
+	case key.Matches(msg, gradientKeys.Add):
+		if i == last {
+			i--
+		}
+		at := (stops[i].At + stops[i+1].At) / 2
+		if stops[i+1].At-stops[i].At < 2*stopGap {
+			m.status = "no room for another stop here"
+			return m, nil
+		}
+		c := banner.Blend(stops[0].Color, stops[last].Color, stops[1:last], at)
+		stops = append(stops[:i+1], append([]banner.Stop{{At: at, Color: c}}, stops[i+1:]...)...)
+		m.setGradientStops(stops)
+		return m, m.selectStop(i + 1)
+	case key.Matches(msg, gradientKeys.Remove):
+		if i == 0 || i == last {
+			m.status = "the endpoints can't be removed"
+			return m, nil
+		}
+		m.setGradientStops(append(stops[:i], stops[i+1:]...))
+		return m, m.selectStop(i - 1)
+	}
+	var cmd tea.Cmd
+	m.gradInput, cmd = m.gradInput.Update(msg)
+	if c, ok := banner.ParseHex(m.gradInput.Value()); ok && len(m.gradInput.Value()) == 7 {
+		stops[i].Color = c
+		m.setGradientStops(stops)
+	}
+	return m, cmd
+}
+
+// gradientView is the editor: the gradient drawn as a bar with a marker
+// under each stop, the selected one bright, and the selected stop's color.
+func (m model) gradientView() string {
+	r := m.v.Renderer()
+	label := r.NewStyle().Faint(true)
+	stops := m.gradientStops()
+	last := len(stops) - 1
+	width := min(max(m.w-8, 16), 64)
+
+	var bar strings.Builder
+	for x := range width {
+		c := banner.Blend(stops[0].Color, stops[last].Color, stops[1:last], float64(x)/float64(width-1))
+		bar.WriteString(r.NewStyle().Foreground(lipgloss.Color(c.Hex())).Render("█"))
+	}
+	marks := []rune(strings.Repeat(" ", width))
+	for _, s := range stops {
+		marks[int(s.At*float64(width-1)+0.5)] = '△'
+	}
+	at := int(stops[m.gradStop].At*float64(width-1) + 0.5)
+	marks[at] = '▲'
+	markers := label.Render(string(marks[:at])) + r.NewStyle().Bold(true).Render("▲") + label.Render(string(marks[at+1:]))
+
+	title := fmt.Sprintf("Gradient  stop %d of %d at %.0f%%", m.gradStop+1, len(stops), stops[m.gradStop].At*100)
+	return label.Render(title) + "  " + m.gradInput.View() + "\n\n" + bar.String() + "\n" + markers
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Render history
+//------------------------------------------------------------------------------
+
+const (
+	historyFileName = "history.jsonl"
+	historyLimit    = 200
+	historyDwell    = 2 * time.Second // how long a look must stay up to count
+	historyRows     = 10              // entries visible in the overlay
+)
+
+type historyEntry struct {
+	Text  string    `json:"text"`
+	Font  string    `json:"font"`
+	Start string    `json:"start"`
+	End   string    `json:"end"`
+	When  time.Time `json:"when"`
+}
+
+func (e historyEntry) same(o historyEntry) bool {
+	return e.Text == o.Text && e.Font == o.Font && e.Start == o.Start && e.End == o.End
+}
+
+// historyDwellMsg fires once a look has been on screen for historyDwell.
+type historyDwellMsg struct{ seq int }
+
+func historyPath() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, historyFileName)
+	}
+	return ""
+}
+
+// loadHistory reads the log, oldest first. A missing or damaged file just
+// yields fewer entries.
+func loadHistory(path string) []historyEntry {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var out []historyEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e historyEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && fontIndex(e.Font) >= 0 {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func saveHistory(path string, entries []historyEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		line, _ := json.Marshal(e)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+func (m model) currentEntry() historyEntry {
+	start, end := m.v.Colors()
+	return historyEntry{Text: m.v.Text(), Font: m.v.Font(), Start: start, End: end}
+}
+
+// noteRendered restarts the dwell timer whenever the displayed look changes.
+func (m *model) noteRendered() tea.Cmd {
+	e := m.currentEntry()
+	if e.same(m.pending) {
+		return nil
+	}
+	m.pending = e
+	m.historySeq++
+	seq := m.historySeq
+	return tea.Tick(historyDwell, func(time.Time) tea.Msg { return historyDwellMsg{seq} })
+}
+
+// recordHistory appends the pending look, moving a repeat to the end, and
+// persists the log.
+func (m *model) recordHistory(seq int) tea.Cmd {
+	if seq != m.historySeq || strings.TrimSpace(m.pending.Text) == "" {
+		return nil
+	}
+	e := m.pending
+	e.When = time.Now()
+	kept := m.history[:0]
+	for _, h := range m.history {
+		if !h.same(e) {
+			kept = append(kept, h)
+		}
+	}
+	m.history = append(kept, e)
+	if len(m.history) > historyLimit {
+		m.history = m.history[len(m.history)-historyLimit:]
+	}
+	path, entries := m.historyFile, append([]historyEntry(nil), m.history...)
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := saveHistory(path, entries); err != nil {
+			return statusMsg("history: " + err.Error())
+		}
+		return nil
+	}
+}
+
+//------------------------------------------------------------------------------
+// History overlay (ctrl+r)
+//------------------------------------------------------------------------------
+
+func (m *model) openHistory() {
+	if len(m.history) == 0 {
+		m.status = "history is empty"
+		return
+	}
+	m.historyOpen = true
+	m.historyCursor = 0
+}
+
+// updateHistory handles keys while the overlay is open. The cursor counts
+// from the newest entry.
+func (m model) updateHistory(msg tea.KeyMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "esc", "ctrl+r", "q":
+		m.historyOpen = false
+	case "up", "k":
+		m.historyCursor = max(0, m.historyCursor-1)
+	case "down", "j":
+		m.historyCursor = min(len(m.history)-1, m.historyCursor+1)
+	case "enter":
+		e := m.history[len(m.history)-1-m.historyCursor]
+		s := m.settings()
+		s.Text, s.Font, s.Start, s.End = e.Text, e.Font, e.Start, e.End
+		cmd = m.applySettings(s)
+		m.historyOpen = false
+		m.status = fmt.Sprintf("applied history: %s / %s", e.Text, e.Font)
+	}
+	return m, cmd
+}
+
+func (m model) historyView() string {
+	r := m.v.Renderer()
+	title := r.NewStyle().Bold(true).Render("History") + r.NewStyle().Faint(true).Render("  (↑/↓, enter to apply, esc to close)")
+	lines := []string{title}
+	first := max(0, m.historyCursor-historyRows+1)
+	for i := first; i < len(m.history) && i < first+historyRows; i++ {
+		e := m.history[len(m.history)-1-i]
+		swatch := r.NewStyle().Foreground(lipgloss.Color(e.Start)).Render("█") +
+			r.NewStyle().Foreground(lipgloss.Color(e.End)).Render("█")
+		line := fmt.Sprintf("%s %-12s %s  %s", swatch, e.Font, e.Text, e.When.Format("Jan 2 15:04"))
+		if i == m.historyCursor {
+			line = viewer.ChipWith(r, line, "0", "212")
+		} else {
+			line = " " + line
+		}
+		lines = append(lines, line)
+	}
+	box := r.NewStyle().Padding(0, 1).Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("212"))
+	return box.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Image viewer (--image PATH, --image-style, ":image")
+//------------------------------------------------------------------------------
+
+// imageStyles are the ways --image is drawn: ASCII art colored like a
+// banner, or the picture's own colors in half blocks.
+var imageStyles = []string{"ascii", "color"}
+
+func checkImageStyle(style string) error {
+	if !slices.Contains(imageStyles, style) {
+		return fmt.Errorf("unknown image style %q (want %s)", style, strings.Join(imageStyles, " or "))
+	}
+	return nil
+}
+
+// openImage loads the picture at path and shows it in place of the banner.
+func (m *model) openImage(path string) error {
+	img, err := banner.LoadImage(path)
+	if err != nil {
+		return err
+	}
+	m.picture = img
+	m.showPicture()
+	return nil
+}
+
+// showPicture draws the picture as large as the terminal leaves room for,
+// or goes back to the --art art or banner text if there is none, in place
+// of any canvas.
+// The terminal size is only known once the first WindowSizeMsg arrives.
+func (m *model) showPicture() {
+	m.dropCanvas()
+	m.pictureView = ""
+	if m.picture == nil {
+		m.v.ShowArt(m.piped)
+		return
+	}
+	w, h := m.w-2, m.h-lipgloss.Height(m.v.ControlsView())-3
+	if m.w == 0 {
+		w, h = 80, 24
+	}
+	if m.imageStyle == "color" {
+		if banner.AmbiguousWide() { // half blocks take two cells
+			w /= 2
+		}
+		m.v.ShowArt(nil)
+		m.pictureView = banner.NewPicture(m.picture, banner.ImageFit(m.picture, w, max(1, h))).Styled(m.v.Renderer())
+		return
+	}
+	art := banner.ImageArt(m.picture, banner.ImageFit(m.picture, w, max(1, h)))
+	m.v.ShowArt(&art)
+}
+
+func cmdImage(m *model, args []string) (string, tea.Cmd) {
+	if m.public {
+		return "image: not available in remote sessions", nil
+	}
+	switch {
+	case len(args) == 1 && args[0] == "off":
+		m.picture = nil
+		m.showPicture()
+		return "image off", nil
+	case len(args) == 1 && slices.Contains(imageStyles, args[0]):
+		m.imageStyle = args[0]
+		m.showPicture()
+		return "image style " + args[0], nil
+	case len(args) > 0:
+		path := strings.Join(args, " ")
+		if err := m.openImage(path); err != nil {
+			return "image: " + err.Error(), nil
+		}
+		return "image " + path, nil
+	}
+	return "usage: image PATH | image ascii | image color | image off", nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+// JSON render jobs (render --json FILE)
+//------------------------------------------------------------------------------
+
+// maxJobBytes bounds a job read from stdin.
+const maxJobBytes = 1 << 20
+
+// applyJob sets fs's flags from the JSON object at path, or stdin for
+// "-": each key is a flag name without the dashes and each value a string,
+// number or boolean, as in {"text": "hi", "font": "slant", "format": "png"}.
+// Flags given on the command line win over the job's.
+func applyJob(fs *flag.FlagSet, path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(io.LimitReader(os.Stdin, maxJobBytes))
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("job: %w", err)
+	}
+	var job map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&job); err != nil {
+		return fmt.Errorf("job: %w", err)
+	}
+	if job == nil {
+		return errors.New("job: want a JSON object")
+	}
+
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	keys := make([]string, 0, len(job))
+	for k := range job {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys) // errors name the same key every run
+	for _, k := range keys {
+		if k == "json" || fs.Lookup(k) == nil {
+			return fmt.Errorf("job: unknown key %q", k)
+		}
+		if given[k] {
+			continue
+		}
+		var v string
+		switch x := job[k].(type) {
+		case string:
+			v = x
+		case json.Number:
+			v = x.String()
+		case bool:
+			v = strconv.FormatBool(x)
+		default:
+			return fmt.Errorf("job: %s: want a string, number or boolean", k)
+		}
+		if err := fs.Set(k, v); err != nil {
+			return fmt.Errorf("job: %s: %w", k, err)
+		}
+	}
+	return nil
+}
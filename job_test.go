@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyJob(t *testing.T) {
+	tests := []struct {
+		name, job string
+		args      []string
+		want      map[string]string // flag values after; nil if the job fails
+	}{
+		{"strings", `{"text": "hi", "font": "slant"}`, nil, map[string]string{"text": "hi", "font": "slant", "fps": "15", "quiet": "false"}},
+		{"number and bool", `{"fps": 30, "quiet": true}`, nil, map[string]string{"text": "", "fps": "30", "quiet": "true"}},
+		{"flags win", `{"text": "job", "fps": 30}`, []string{"-text", "flag"}, map[string]string{"text": "flag", "fps": "30"}},
+		{"empty", `{}`, nil, map[string]string{"text": "", "fps": "15"}},
+		{"unknown key", `{"colour": "red"}`, nil, nil},
+		{"json key", `{"json": "other.json"}`, nil, nil},
+		{"bad value", `{"fps": "fast"}`, nil, nil},
+		{"nested", `{"text": ["a"]}`, nil, nil},
+		{"null", `{"text": null}`, nil, nil},
+		{"not an object", `["text"]`, nil, nil},
+		{"top-level null", `null`, nil, nil},
+		{"broken", `{"text": `, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("render", flag.ContinueOnError)
+			fs.String("text", "", "")
+			fs.String("font", "standard", "")
+			fs.Float64("fps", 15, "")
+			fs.Bool("quiet", false, "")
+			fs.String("json", "", "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "job.json")
+			if err := os.WriteFile(path, []byte(tt.job), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := applyJob(fs, path)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("applyJob succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range tt.want {
+				if got := fs.Lookup(name).Value.String(); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+	if err := applyJob(flag.NewFlagSet("render", flag.ContinueOnError), filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("applyJob succeeded on a missing file")
+	}
+}
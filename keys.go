@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Key bindings and the hints footer
+//------------------------------------------------------------------------------
+
+// appKeyMap is the app's own bindings, on top of the viewer's. Prompts
+// have theirs too, so the footer can say what works in each.
+type appKeyMap struct {
+	Quit, Command, FontJump, History, Edit, Freeze, Reset key.Binding
+	Gradient, LoadSlot, SaveSlot                          key.Binding
+
+	Run, Cancel          key.Binding // command line and font prompt
+	Complete, Jump, Skip key.Binding // font prompt
+}
+
+var appKeys = appKeyMap{
+	Quit:     key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+	Command:  key.NewBinding(key.WithKeys(":"), key.WithHelp(":", "command")),
+	FontJump: key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "go to font")),
+	History:  key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "history")),
+	Edit:     key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "edit art")),
+	Freeze:   key.NewBinding(key.WithKeys("ctrl+y"), key.WithHelp("ctrl+y", "freeze colors")),
+	Reset:    key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "reset")),
+	Gradient: key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "gradient")),
+	LoadSlot: key.NewBinding(key.WithKeys("alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9"), key.WithHelp("alt+1-9", "load slot")),
+	SaveSlot: key.NewBinding(key.WithKeys("alt+!", "alt+@", "alt+#", "alt+$", "alt+%", "alt+^", "alt+&", "alt+*", "alt+("), key.WithHelp("alt+shift+1-9", "save slot")),
+
+	Run:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")),
+	Cancel:   key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "cancel")),
+	Complete: key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab", "complete")),
+	Jump:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "jump")),
+	Skip:     key.NewBinding(key.WithKeys("]", "["), key.WithHelp("25]/25[", "skip fonts")),
+}
+
+// gradientKeyMap is the gradient editor's bindings.
+type gradientKeyMap struct {
+	Select, Move, Add, Remove, Done key.Binding
+}
+
+var gradientKeys = gradientKeyMap{
+	Select: key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab", "next stop")),
+	Move:   key.NewBinding(key.WithKeys("shift+left", "shift+right"), key.WithHelp("shift+←/→", "move")),
+	Add:    key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "add stop")),
+	Remove: key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "remove")),
+	Done:   key.NewBinding(key.WithKeys("esc", "enter", "ctrl+g"), key.WithHelp("esc", "done")),
+}
+
+// viewerKeys is the viewer's map less enter, which the app doesn't use.
+func viewerKeys() viewer.KeyMap {
+	k := viewer.DefaultKeyMap()
+	k.Select.SetHelp("", "")
+	return k
+}
+
+// footer is the key hints for what has the keyboard, as many as fit the
+// terminal; "" where the view has hints of its own.
+func (m model) footer() string {
+	var bindings []key.Binding
+	switch {
+	case m.confirm != nil, m.historyOpen, m.editing:
+		return ""
+	case m.cmdActive:
+		bindings = []key.Binding{appKeys.Run, appKeys.Cancel}
+	case m.fontActive:
+		bindings = []key.Binding{appKeys.Complete, appKeys.Jump, appKeys.Skip, appKeys.Cancel}
+	case m.gradOpen:
+		bindings = []key.Binding{gradientKeys.Select, gradientKeys.Move, gradientKeys.Add, gradientKeys.Remove, gradientKeys.Done}
+	default:
+		bindings = append(m.v.KeyHints(), appKeys.FontJump, appKeys.Command, appKeys.Gradient, appKeys.Edit, appKeys.Freeze, appKeys.Reset, appKeys.LoadSlot, appKeys.SaveSlot, appKeys.History, appKeys.Quit)
+	}
+	return viewer.HintsView(m.v.Renderer(), bindings, m.w)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Live banner (shared, animated state behind serve http)
+//------------------------------------------------------------------------------
+
+// liveBanner is one animated banner shared by every connected client. Any
+// client may change it; every client sees each frame.
+type liveBanner struct {
+	mu      sync.Mutex
+	s       settings
+	art     banner.Art
+	hue     *banner.HueCycle
+	clients map[*liveClient]struct{}
+	back    *backdrop // drawn behind every frame, if set
+}
+
+// liveClient is one subscriber. Slow clients miss frames rather than
+// holding up the others.
+type liveClient struct {
+	send chan []byte
+}
+
+// liveMsg is pushed to clients on every frame.
+type liveMsg struct {
+	Text  string       `json:"text"`
+	Font  string       `json:"font"`
+	Start string       `json:"start"`
+	End   string       `json:"end"`
+	Mode  string       `json:"mode"`
+	Frame banner.Frame `json:"frame"`
+}
+
+func newLiveBanner(s settings) (*liveBanner, error) {
+	l := &liveBanner{hue: banner.NewHueCycle(s.StepDeg), clients: map[*liveClient]struct{}{}}
+	if err := l.set(s); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// set recomposes the art for s. Callers hold l.mu or own l exclusively.
+func (l *liveBanner) set(s settings) error {
+	start := time.Now()
+	art, err := banner.Compose(s.Text, banner.WithOptions(s.options(0)))
+	if err != nil {
+		return err
+	}
+	metrics.rendered("live", s.Font, time.Since(start))
+	l.s, l.art = s, art
+	l.hue.Step = s.StepDeg
+	l.hue.Init(art.Width, len(art.Rows))
+	return nil
+}
+
+// update overlays values (setting keys to values) onto the live settings.
+func (l *liveBanner) update(values map[string]string) error {
+	return l.change(func(s *settings) error {
+		return applyLayer(s, "update", func(key string) (string, bool) {
+			v, ok := values[key]
+			return v, ok
+		})
+	})
+}
+
+// change edits a copy of the live settings with fn and switches to it if
+// the result is valid.
+func (l *liveBanner) change(fn func(s *settings) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.s
+	if err := fn(&s); err != nil {
+		return err
+	}
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if err := checkServeText(s.Text); err != nil {
+		return err
+	}
+	return l.set(s)
+}
+
+// message renders the current frame for clients.
+func (l *liveBanner) message() liveMsg {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	start := time.Now()
+	f := l.hue.Apply(l.art.Colorize(l.s.options(0)))
+	if l.back != nil {
+		f = l.back.behind(f, 0, 0)
+	}
+	metrics.framed(time.Since(start))
+	return liveMsg{Text: l.s.Text, Font: l.s.Font, Start: l.s.Start, End: l.s.End, Mode: l.s.Mode, Frame: f}
+}
+
+func (l *liveBanner) subscribe() *liveClient {
+	c := &liveClient{send: make(chan []byte, 1)}
+	l.mu.Lock()
+	l.clients[c] = struct{}{}
+	l.mu.Unlock()
+	return c
+}
+
+func (l *liveBanner) unsubscribe(c *liveClient) {
+	l.mu.Lock()
+	delete(l.clients, c)
+	l.mu.Unlock()
+}
+
+// run advances the animation and pushes a frame to every client each
+// interval, until ctx is done.
+func (l *liveBanner) run(ctx context.Context) {
+	for {
+		l.mu.Lock()
+		interval, animate, idle := l.s.Interval, l.s.Animate, len(l.clients) == 0
+		if animate {
+			l.hue.Tick()
+		}
+		l.mu.Unlock()
+
+		if !idle {
+			l.broadcast()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (l *liveBanner) broadcast() {
+	b, err := json.Marshal(l.message())
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for c := range l.clients {
+		select {
+		case c.send <- b:
+		default: // still sending the last frame; skip this one
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+// WebSocket endpoint (/ws)
+//------------------------------------------------------------------------------
+
+// serveWS mirrors the live banner to a WebSocket client and applies the
+// setting updates it sends, e.g. {"text":"DEPLOYED","start":"#ff0000"}.
+// With --token only clients that connected with it may send updates; the
+// rest are mirrors. Rejected updates are answered with {"error":"..."}.
+// Browsers may connect only from pages on this host unless --any-origin
+// is given.
+func (srv *renderServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := srv.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has replied
+	}
+	defer conn.Close()
+	canUpdate := srv.token == "" || hasToken(r, srv.token)
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	c := srv.live.subscribe()
+	defer srv.live.unsubscribe(c)
+	defer metrics.session("ws")()
+	replies := make(chan []byte, 8)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadLimit(4096)
+		for {
+			var values map[string]any
+			if err := conn.ReadJSON(&values); err != nil {
+				return // closed, or not JSON; either way we're done
+			}
+			var reply error
+			switch bad := unknownKeys(values); {
+			case !canUpdate:
+				reply = errors.New("unauthorized: connect with ?token=TOKEN to send updates")
+			case len(bad) > 0:
+				reply = fmt.Errorf("unknown keys %s", strings.Join(bad, ", "))
+			case srv.limit.rate > 0 && !srv.limit.allow(client, time.Now()):
+				reply = errors.New("rate limit exceeded")
+			default:
+				reply = srv.live.update(stringValues(values))
+			}
+			if reply != nil {
+				b, _ := json.Marshal(map[string]string{"error": reply.Error()})
+				select {
+				case replies <- b:
+				default:
+				}
+			}
+		}
+	}()
+
+	for {
+		var b []byte
+		select {
+		case b = <-c.send:
+		case b = <-replies:
+		case <-done:
+			return
+		}
+		conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.WriteMessage(websocket.TextMessage, b); err != nil {
+			return
+		}
+	}
+}
+
+// stringValues flattens decoded JSON scalars into setting strings.
+func stringValues(values map[string]any) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
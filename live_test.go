@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"glamdm/pkg/banner"
+)
+
+func TestLiveBannerOptions(t *testing.T) {
+	s := defaultSettings()
+	s.Text, s.Mode, s.Normalize = "café", banner.ModeBlock.Name(), "strip"
+	l, err := newLiveBanner(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := banner.Compose(s.Text, banner.WithOptions(s.options(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(l.art, want) {
+		t.Error("live art isn't composed with the settings' options")
+	}
+}
+
+func TestServeWSOrigin(t *testing.T) {
+	tests := []struct {
+		name      string
+		origin    string
+		anyOrigin bool
+		ok        bool
+	}{
+		{"no origin", "", false, true},
+		{"same host", "same", false, true},
+		{"other site", "http://evil.example", false, false},
+		{"other site allowed", "http://evil.example", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live, err := newLiveBanner(defaultSettings())
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := &renderServer{live: live, limit: newRateLimiter(5, 10)}
+			if tt.anyOrigin {
+				srv.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+			}
+			ts := httptest.NewServer(http.HandlerFunc(srv.serveWS))
+			defer ts.Close()
+
+			header := http.Header{}
+			switch tt.origin {
+			case "same":
+				header.Set("Origin", ts.URL)
+			case "":
+			default:
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), header)
+			if conn != nil {
+				conn.Close()
+			}
+			if (err == nil) != tt.ok {
+				t.Fatalf("dial: %v (response %v), want ok %v", err, resp, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLiveMetrics(t *testing.T) {
+	l, err := newLiveBanner(defaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := func() (renders, frames uint64) {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return metrics.renders["live"], metrics.frame.count
+	}
+	renders, frames := counts()
+	for range 3 {
+		l.message()
+	}
+	if err := l.update(map[string]string{"text": "changed"}); err != nil {
+		t.Fatal(err)
+	}
+	gotRenders, gotFrames := counts()
+	if gotRenders-renders != 1 || gotFrames-frames != 3 {
+		t.Errorf("%d renders and %d frames counted, want 1 and 3", gotRenders-renders, gotFrames-frames)
+	}
+}
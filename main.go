@@ -1,253 +1,118 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math"
+	"image"
 	"os"
 	"strings"
 	"time"
-	"unicode/utf8"
 
+	"glamdm/pkg/banner"
+	"glamdm/pkg/colorexpr"
+	"glamdm/pkg/viewer"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	figure "github.com/common-nighthawk/go-figure"
 )
 
 // Build & run:
-//   go mod init glamdm
-//   go get github.com/charmbracelet/bubbletea \
-//          github.com/charmbracelet/bubbles \
-//          github.com/charmbracelet/lipgloss \
-//          github.com/common-nighthawk/go-figure
-//   go run .
-// Quit with q or Ctrl+C.
-
-// Notes:
-// - Cycle fonts with ←/→ (left/right) or [/] .
-// - Edit fields with Tab to move focus.
-// - Text updates live; colors apply as you type valid hex (e.g. #8A2BE2).
-// - Press 'm' to toggle render mode (BLOCK/GLYPH/LIGHT/DOTS).
-// - Press 'a' to toggle animated hue cycling. Use '+' and '-' to change speed.
+//   go build -o ascii-viewer .
+//   ./ascii-viewer
+// Quit with q or Ctrl+C. readme.md covers the keys, commands, render
+// formats, servers and configuration.
+//
+// The rendering core (FIGlet composition, gradients, render modes) lives in
+// pkg/banner so other programs can import glamdm/pkg/banner; the editor and
+// preview are an embeddable Bubble Tea component in pkg/viewer.
 
 //------------------------------------------------------------------------------
 // Model & Types
 //------------------------------------------------------------------------------
 
-type renderMode int
-
-const (
-	modeBlock renderMode = iota // Replace glyphs with full block (█)
-	modeGlyph                   // Keep original FIGlet glyphs
-	modeLight                   // Medium block (▓)
-	modeDots                    // Dotted look (·)
-)
-
-var modeNames = []string{"BLOCK █", "GLYPH", "LIGHT ▓", "DOTS ·"}
-
-type colorRGB struct{ R, G, B int }
-
-func (c colorRGB) Hex() string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
-
-func lerp(a, b colorRGB, t float64) colorRGB {
-	return colorRGB{
-		R: int(float64(a.R) + (float64(b.R)-float64(a.R))*t),
-		G: int(float64(a.G) + (float64(b.G)-float64(a.G))*t),
-		B: int(float64(a.B) + (float64(b.B)-float64(a.B))*t),
-	}
-}
-
-func parseHexColor(s string) (colorRGB, bool) {
-	s = strings.TrimSpace(s)
-	if !strings.HasPrefix(s, "#") {
-		return colorRGB{}, false
-	}
-	s = s[1:]
-	var r, g, b int
-	switch len(s) {
-	case 6:
-		_, err := fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
-		return colorRGB{r, g, b}, err == nil
-	case 3:
-		var r1, g1, b1 byte
-		_, err := fmt.Sscanf(s, "%1x%1x%1x", &r1, &g1, &b1)
-		return colorRGB{int(r1) * 17, int(g1) * 17, int(b1) * 17}, err == nil
-	default:
-		return colorRGB{}, false
-	}
-}
-
-// HSV helpers for hue rotation
-func clamp01(x float64) float64 { return math.Max(0, math.Min(1, x)) }
-
-func rgbToHsv(c colorRGB) (h, s, v float64) {
-	r := float64(c.R) / 255.0
-	g := float64(c.G) / 255.0
-	b := float64(c.B) / 255.0
-	maxv := math.Max(r, math.Max(g, b))
-	minv := math.Min(r, math.Min(g, b))
-	d := maxv - minv
-	v = maxv
-	if maxv == 0 { // black
-		return 0, 0, 0
-	}
-	s = 0
-	if maxv != 0 {
-		s = d / maxv
-	}
-	if d == 0 {
-		h = 0
-	} else {
-		switch maxv {
-		case r:
-			h = (g - b) / d
-			if g < b {
-				h += 6
-			}
-		case g:
-			h = (b-r)/d + 2
-		case b:
-			h = (r-g)/d + 4
-		}
-		h *= 60
-	}
-	return
-}
-
-func hsvToRgb(h, s, v float64) colorRGB {
-	h = math.Mod(h, 360)
-	if h < 0 {
-		h += 360
-	}
-	c := v * s
-	x := c * (1 - math.Abs(math.Mod(h/60.0, 2)-1))
-	m := v - c
-	var r1, g1, b1 float64
-	switch {
-	case h < 60:
-		r1, g1, b1 = c, x, 0
-	case h < 120:
-		r1, g1, b1 = x, c, 0
-	case h < 180:
-		r1, g1, b1 = 0, c, x
-	case h < 240:
-		r1, g1, b1 = 0, x, c
-	case h < 300:
-		r1, g1, b1 = x, 0, c
-	default:
-		r1, g1, b1 = c, 0, x
-	}
-	return colorRGB{int((r1 + m) * 255), int((g1 + m) * 255), int((b1 + m) * 255)}
-}
-
-func rotateHue(c colorRGB, delta float64) colorRGB {
-	h, s, v := rgbToHsv(c)
-	return hsvToRgb(h+delta, s, v)
-}
-
-// Messages for animation tick
-type tickMsg time.Time
-
-func tickEvery(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg { return tickMsg(t) })
-}
+// statusMsg replaces the status line text.
+type statusMsg string
 
+// model is the standalone app: the embeddable viewer plus the command
+// prompt, history, and config hot-reload around it.
 type model struct {
 	w, h int
 
-	// Controls
-	inputs     []textinput.Model // 0=text, 1=start hex, 2=end hex
-	focusIndex int
-
-	fonts     []string
-	fontIndex int
-
-	// Render cache
-	artLines []string
-	maxWidth int
-
-	// Colors (base are user-chosen; effective may be hue-rotated)
-	baseStart colorRGB
-	baseEnd   colorRGB
-
-	// Mode
-	mode renderMode
-
-	// Animation
-	animate  bool
-	hueShift float64       // degrees
-	stepDeg  float64       // degrees per tick
-	interval time.Duration // tick interval
-}
-
-// FIGlet fonts list
-var figFonts = []string{
-	"standard", "big", "doom", "slant", "shadow", "block", "banner", "larry3d", "speed", "smslant", "small", "isometric1",
-	"3-d", "3x5", "5lineoblique", "acrobatic", "alligator", "alligator2", "alphabet",
-	"avatar", "banner3-D", "banner3", "banner4", "barbwire", "basic", "bell", "bigchief",
-	"binary", "bubble", "bulbhead", "calgphy2", "caligraphy", "catwalk", "chunky",
-	"coinstak", "colossal", "computer", "contessa", "contrast", "cosmic", "cosmike",
-	"cricket", "cursive", "cyberlarge", "cybermedium", "cybersmall", "diamond", "digital", "doh", "dotmatrix", "drpepper",
-	"eftichess", "eftifont", "eftipiti", "eftirobot", "eftitalic", "eftiwall", "eftiwater",
-	"epic", "fender", "fourtops", "fuzzy", "goofy", "gothic", "graffiti", "hollywood",
-	"invita", "isometric2", "isometric3", "isometric4", "italic", "ivrit", "jazmine",
-	"jerusalem", "katakana", "kban", "lcd", "lean", "letters", "linux", "lockergnome",
-	"madrid", "marquee", "maxfour", "mike", "mini", "mirror", "mnemonic", "morse",
-	"moscow", "nancyj-fancy", "nancyj-underlined", "nancyj", "nipples", "ntgreek", "o8",
-	"ogre", "pawp", "peaks", "pebbles", "pepper", "poison", "puffy", "pyramid", "rectangles",
-	"relief", "relief2", "rev", "roman", "rot13", "rounded", "rowancap", "rozzo", "runic",
-	"runyc", "sblood", "script", "serifcap", "short", "slide", "slscript", "smisome1", "smkeyboard",
-	"smscript", "smshadow", "smtengwar", "stampatello", "starwars", "stellar", "stop",
-	"straight", "tanja", "tengwar", "term", "thick", "thin", "threepoint", "ticks", "ticksslant",
-	"tinker-toy", "tombstone", "trek", "tsalagi", "twopoint", "univers", "usaflag", "wavy",
-	"weird",
+	v      viewer.Model
+	public bool // remote session: nothing may be written to the config dir
+
+	// Command prompt and status line
+	cmdline   textinput.Model
+	cmdActive bool
+	status    string
+
+	// Font prompt ("g")
+	fontPrompt textinput.Model
+	fontActive bool
+	fontBase   string // what was typed before tab completion began
+	fontTab    int    // match tab last completed to; -1 before the first tab
+
+	// Gradient stop editor (ctrl+g)
+	gradInput textinput.Model // color of the selected stop
+	gradOpen  bool
+	gradStop  int // selected stop; 0 is the start, the last the end
+
+	// Config hot-reload
+	watched []string // files polled for changes
+	flags   cliFlags // startup flags, reapplied on reload
+	loaded  settings // settings as last loaded from disk
+	preset  settings // look last loaded from disk or a theme, for ":reset"
+
+	confirm       *confirmation // question waiting for an answer, if any
+	quitAfterSave bool          // quit once the theme being named is saved
+
+	slots     slots  // alt+1-9
+	slotsFile string // "" keeps the slots in memory
+
+	// History
+	history       []historyEntry // oldest first
+	historyFile   string
+	pending       historyEntry // look waiting out the dwell timer
+	historySeq    int
+	historyOpen   bool
+	historyCursor int
+
+	feed         feed         // source of the banner text, if not the keyboard
+	finishAction finishAction // when a countdown feed ends
+	flashFrom    [2]string    // colors to restore after flashing
+	caption      string       // plain text the feed shows under the banner
+	untinted     [2]string    // colors to restore when a feed's tint lifts
+
+	piped       *banner.Art // --art art, shown instead of the banner text
+	picture     image.Image // shown instead of the banner, if set
+	imageStyle  string      // how the picture is drawn: ascii or color
+	pictureView string      // the picture drawn in color, if it is
+
+	artFile *artFile // .ans or .nfo file being viewed, if any
+
+	canvas  *canvas // hand edits to the art, shown while set
+	editing bool    // keys go to the canvas
+
+	script  banner.Effect     // built-in or Lua effect, if one is loaded
+	formula *colorexpr.Effect // ":color" formula, if one is set
+
+	runFor time.Duration // quit after this long, if set
 }
 
-func newTextInput(placeholder string, value string) textinput.Model {
-	ti := textinput.New()
-	ti.Placeholder = placeholder
-	ti.SetValue(value)
-	ti.Prompt = ""
-	ti.CharLimit = 256
-	ti.Width = max(12, utf8.RuneCountInString(value)+4)
-	return ti
-}
-
-func newModel() model {
+func newModel(s settings) model {
 	m := model{
-		fonts:     figFonts,
-		fontIndex: 0,
-		baseStart: colorRGB{138, 43, 226}, // #8A2BE2
-		baseEnd:   colorRGB{0, 255, 255},  // #00FFFF
-		mode:      modeGlyph,              // default: keep original glyphs
-		animate:   true,
-		hueShift:  0,
-		stepDeg:   3,                     // degrees per tick
-		interval:  60 * time.Millisecond, // ~16 FPS
-	}
-	m.inputs = []textinput.Model{
-		newTextInput("text", "glam dm"),
-		newTextInput("start hex", "#8A2BE2"),
-		newTextInput("end hex", "#00FFFF"),
-	}
-	m.inputs[0].Focus()
-	m.rebuildArt()
-	return m
-}
-
-func (m *model) rebuildArt() {
-	txt := m.inputs[0].Value()
-	font := m.fonts[m.fontIndex]
-	fig := figure.NewFigure(txt, font, true)
-	lines := strings.Split(strings.TrimRight(fig.String(), "\n"), "\n")
-	maxW := 0
-	for _, l := range lines {
-		if len(l) > maxW {
-			maxW = len(l)
-		}
+		v:          viewer.New(),
+		cmdline:    newCommandLine(),
+		fontPrompt: newFontPrompt(),
+		gradInput:  newStopInput(),
 	}
-	m.artLines = lines
-	m.maxWidth = maxW
+	m.v.SetDebugLog(debugLog)
+	m.v.SetKeys(viewerKeys())
+	_ = m.applySettings(s) // Init starts the animation
+	m.loaded, m.preset = s, s
+	return m
 }
 
 //------------------------------------------------------------------------------
@@ -255,181 +120,339 @@ func (m *model) rebuildArt() {
 //------------------------------------------------------------------------------
 
 func (m model) Init() tea.Cmd {
-	if m.animate {
-		return tickEvery(m.interval)
+	cmds := []tea.Cmd{m.v.Init()}
+	if len(m.watched) > 0 {
+		cmds = append(cmds, pollConfig(m.watched, m.flags, modTimes(m.watched)))
+	}
+	if m.feed != nil {
+		cmds = append(cmds, pollFeed(m.feed, time.Now()))
 	}
-	return nil
+	if m.runFor > 0 {
+		cmds = append(cmds, exitAfter(m.runFor))
+	}
+	return guard(tea.Batch(cmds...))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	debugMsg(msg)
+	before := m.settings()
+	m, cmd := m.update(msg)
+	m.checkScript()
+	debugState(before, m.settings())
+	return m, guard(tea.Batch(cmd, m.noteRendered()))
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.w, m.h = msg.Width, msg.Height
+		m.v.SetWidth(msg.Width)
+		if m.picture != nil && m.canvas == nil { // a redraw would lose the edits
+			m.showPicture()
+		}
 		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
-			return m, tea.Quit
-		case "tab", "shift+tab":
-			if msg.String() == "shift+tab" {
-				m.focusIndex--
-			} else {
-				m.focusIndex++
-			}
-			if m.focusIndex < 0 {
-				m.focusIndex = len(m.inputs) - 1
-			}
-			if m.focusIndex >= len(m.inputs) {
-				m.focusIndex = 0
-			}
-			for i := range m.inputs {
-				if i == m.focusIndex {
-					m.inputs[i].Focus()
-				} else {
-					m.inputs[i].Blur()
-				}
-			}
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		if m.cmdActive {
+			return m.updateCommandLine(msg)
+		}
+		if m.fontActive {
+			return m.updateFontPrompt(msg)
+		}
+		if m.historyOpen {
+			return m.updateHistory(msg)
+		}
+		if m.gradOpen {
+			return m.updateGradient(msg)
+		}
+		if m.artFile != nil {
+			return m.updateArtFile(msg)
+		}
+		if m.editing {
+			return m.updateCanvas(msg)
+		}
+		switch {
+		case key.Matches(msg, appKeys.Quit):
+			return m, m.quit()
+		case key.Matches(msg, appKeys.Command):
+			return m, m.openCommandLine()
+		case key.Matches(msg, appKeys.FontJump):
+			return m, m.openFontPrompt()
+		case key.Matches(msg, appKeys.Gradient):
+			return m, m.openGradient()
+		case key.Matches(msg, appKeys.History):
+			m.openHistory()
 			return m, nil
-		case "left", "[":
-			m.fontIndex = (m.fontIndex - 1 + len(m.fonts)) % len(m.fonts)
-			m.rebuildArt()
+		case key.Matches(msg, appKeys.Edit):
+			m.startEditing()
 			return m, nil
-		case "right", "]":
-			m.fontIndex = (m.fontIndex + 1) % len(m.fonts)
-			m.rebuildArt()
+		case key.Matches(msg, appKeys.Freeze):
+			m.copyColors()
 			return m, nil
-		case "m":
-			m.mode = (m.mode + 1) % renderMode(len(modeNames))
+		case key.Matches(msg, appKeys.Reset):
+			m.status = m.askReset(false)
 			return m, nil
-		case "a":
-			m.animate = !m.animate
-			if m.animate {
-				return m, tickEvery(m.interval)
+		case key.Matches(msg, appKeys.LoadSlot, appKeys.SaveSlot):
+			i, save := slotKey(msg.String())
+			if save {
+				return m, m.saveSlot(i)
 			}
-			return m, nil
-		case "+", "=":
-			m.stepDeg = math.Min(30, m.stepDeg+0.5)
-			return m, nil
-		case "-", "_":
-			m.stepDeg = math.Max(0.5, m.stepDeg-0.5)
-			return m, nil
-		}
-	case tickMsg:
-		if m.animate {
-			m.hueShift = math.Mod(m.hueShift+m.stepDeg, 360)
-			return m, tickEvery(m.interval)
+			return m, m.loadSlot(i)
 		}
+	case exitMsg:
+		return m, tea.Quit
+	case configPollMsg:
+		return m, pollConfig(m.watched, m.flags, msg.mods)
+	case configReloadMsg:
+		return m.handleConfigReload(msg)
+	case controlMsg:
+		return m.handleControl(msg)
+	case mqttMsg:
+		return m.handleMQTT(msg)
+	case feedMsg:
+		return m.handleFeed(msg)
+	case flashMsg:
+		return m.handleFlash(msg)
+	case signalMsg:
+		return m.handleSignal(msg)
+	case historyDwellMsg:
+		return m, m.recordHistory(msg.seq)
+	case statusMsg:
+		m.status = string(msg)
 		return m, nil
 	}
 
-	// Update inputs and live-apply changes
-	var cmds []tea.Cmd
-	for i := range m.inputs {
-		var cmd tea.Cmd
-		m.inputs[i], cmd = m.inputs[i].Update(msg)
-		cmds = append(cmds, cmd)
-	}
+	v, cmd := m.v.Update(msg)
+	m.v = v.(viewer.Model)
+	return m, cmd
+}
 
-	// Text changes rebuild art
-	m.rebuildArt()
+func (m model) View() string {
+	if m.w == 0 || m.h == 0 {
+		return "\n  loading…"
+	}
 
-	// Colors update when valid (these are bases for hue rotation)
-	if c, ok := parseHexColor(m.inputs[1].Value()); ok {
-		m.baseStart = c
+	r := m.v.Renderer()
+	if m.artFile != nil {
+		return r.Place(m.w, m.h, lipgloss.Center, lipgloss.Top, m.artFileView())
 	}
-	if c, ok := parseHexColor(m.inputs[2].Value()); ok {
-		m.baseEnd = c
+	labelStyle := r.NewStyle().Faint(true)
+	controls := m.v.ControlsView()
+	if m.historyOpen {
+		controls = m.historyView()
+	} else if m.gradOpen {
+		controls = m.gradientView()
+	}
+	art := m.v.PreviewView()
+	if m.pictureView != "" {
+		art = m.pictureView
 	}
 
-	return m, tea.Batch(cmds...)
+	// Layout: controls on top, art centered below, prompt/status last
+	gap := strings.Repeat("\n", 1)
+	content := controls + gap + art
+	if m.caption != "" {
+		content += gap + m.caption
+	}
+	if m.confirm != nil {
+		content += gap + m.confirm.question
+	} else if m.cmdActive {
+		content += gap + m.cmdline.View()
+	} else if m.fontActive {
+		content += gap + m.fontPromptView()
+	} else if m.editing && m.status == "" {
+		content += gap + labelStyle.Render(m.canvasStatus())
+	} else if m.status != "" {
+		content += gap + labelStyle.Render(m.status)
+	}
+	if f := m.footer(); f != "" {
+		content += gap + f
+	}
+	return r.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, content)
 }
 
-func (m model) View() string {
-	if m.w == 0 || m.h == 0 {
-		return "\n  loading…"
+// runSubcommand handles non-interactive commands such as "theme export".
+func runSubcommand(flags cliFlags, args []string) error {
+	switch args[0] {
+	case "theme":
+		return runThemeCommand(args[1:])
+	case "render":
+		return runRender(flags, args[1:])
+	case "serve":
+		return runServe(flags, args[1:])
 	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
 
-	// Effective colors (possibly hue-rotated)
-	effStart := m.baseStart
-	effEnd := m.baseEnd
-	if m.animate {
-		effStart = rotateHue(effStart, m.hueShift)
-		effEnd = rotateHue(effEnd, m.hueShift)
+func main() { os.Exit(run()) }
+
+// run is the program, returning its exit status, so deferred cleanups such
+// as flushing --trace and --debug run on every path out.
+func run() int {
+	var flags cliFlags
+	flags.register(flag.CommandLine)
+	pprofAddr := flag.String("pprof", "", "serve net/http/pprof on this address, e.g. :6060")
+	traceFile := flag.String("trace", "", "write an execution trace to this file")
+	showCaps := flag.Bool("caps", false, "print the detected terminal capabilities and exit")
+	debugFile := flag.String("debug", "", "append a debug log of keys, state changes, render timings and font errors to this file")
+	flag.BoolVar(&noConfig, "no-config", false, "ignore the config dir and project profiles: no config files, themes, custom fonts or effects, and history and slots kept in memory")
+	flag.Parse()
+	if err := envFlags(flag.CommandLine); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
 	}
 
-	// Controls panel
-	labelStyle := lipgloss.NewStyle().Faint(true)
-	box := lipgloss.NewStyle().Padding(0, 1).Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+	caps := detectCaps(os.Getenv, lipgloss.ColorProfile())
+	if flags.legacyConsole() {
+		caps.setLegacy()
+		lipgloss.SetColorProfile(caps.color)
+	}
+	if *showCaps {
+		caps.report(os.Stdout)
+		return 0
+	}
 
-	animState := "off"
-	if m.animate {
-		animState = fmt.Sprintf("on (%.1f°/tick)", m.stepDeg)
+	listenPprof(*pprofAddr)
+	stopTrace, err := startTrace(*traceFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
 	}
-	ctrlLines := []string{
-		labelStyle.Render("Text:") + " " + m.inputs[0].View(),
-		labelStyle.Render("Start:") + " " + m.inputs[1].View(),
-		labelStyle.Render("End:") + " " + m.inputs[2].View(),
-		labelStyle.Render("Font:") + " " + currentChip(m.fonts[m.fontIndex], "212", "57") + "  (←/→ or [/])",
-		labelStyle.Render("Mode:") + " " + currentChip(modeNames[m.mode], "118", "237") + "  (m)",
-		labelStyle.Render("Hue cycle:") + " " + currentChip(animState, "51", "240") + "  (a, +/-)",
+	defer stopTrace()
+	closeDebug, err := openDebugLog(*debugFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
 	}
-	controls := box.Render(strings.Join(ctrlLines, "\n"))
+	defer closeDebug()
 
-	// Build colored art from ASCII using per-column gradient & render modes
-	rows := make([]string, len(m.artLines))
-	for y, line := range m.artLines {
-		if len(line) < m.maxWidth {
-			line += strings.Repeat(" ", m.maxWidth-len(line))
-		}
-		var b strings.Builder
-		for x := 0; x < m.maxWidth; x++ {
-			ch := line[x]
-			if ch == ' ' {
-				b.WriteByte(' ')
-				continue
-			}
-			t := 0.0
-			if m.maxWidth > 1 {
-				t = float64(x) / float64(m.maxWidth-1)
-			}
-			c := lerp(effStart, effEnd, t)
-			style := lipgloss.NewStyle().Foreground(lipgloss.Color(c.Hex()))
-			switch m.mode {
-			case modeBlock:
-				b.WriteString(style.Render("█"))
-			case modeLight:
-				b.WriteString(style.Render("▓"))
-			case modeDots:
-				b.WriteString(style.Render("·"))
-			case modeGlyph:
-				b.WriteString(style.Render(string(ch)))
-			}
+	loadCustomFonts(fontsDir())
+
+	if args := flag.Args(); len(args) > 0 {
+		if err := runSubcommand(flags, args); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
 		}
-		rows[y] = b.String()
+		return 0
+	}
+	if !isTerminal(os.Stdout) { // cron, a systemd timer or a pipe
+		fmt.Fprintln(os.Stderr, "error: stdout is not a terminal; to write a banner from a script, use render --output FILE")
+		return 2
 	}
-	art := strings.Join(rows, "\n")
-
-	// Layout: controls on top, art centered below
-	gap := strings.Repeat("\n", 1)
-	content := controls + gap + art
-	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, content)
-}
 
-func currentChip(name, fg, bg string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(fg)).Background(lipgloss.Color(bg)).Padding(0, 1).Render(name)
-}
+	s, err := loadSettings(flags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
+	}
+	note := caps.adjust(&s)
+	if flags.collage != "" || len(flags.panels) > 0 {
+		if err := runCollage(flags, s); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
+		}
+		return 0
+	}
 
-func max(a, b int) int {
-	if a > b {
-		return a
+	m := newModel(s)
+	m.status = note
+	m.flags = flags
+	m.watched = watchedFiles(flags)
+	m.historyFile = historyPath()
+	m.history = loadHistory(m.historyFile)
+	m.slotsFile = slotsPath()
+	m.slots = loadSlots(m.slotsFile)
+	if flags.effect != "" {
+		e, err := loadEffect(flags.effect)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
+		}
+		m.setScript(e)
+	}
+	if flags.color != "" {
+		e, err := colorexpr.Parse(flags.color)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error: color:", err)
+			return 2
+		}
+		m.setFormula(e)
+	}
+	if m.feed, err = newFeed(flags); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
+	}
+	if m.finishAction, err = parseFinish(flags.finish); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
+	}
+	if err := checkImageStyle(flags.imageStyle); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
+	}
+	m.imageStyle = flags.imageStyle
+	if flags.view != "" {
+		if m.artFile, err = openArtFile(flags.view); err != nil {
+			fmt.Fprintln(os.Stderr, "error: view:", err)
+			return 2
+		}
+	}
+	if err := checkArtSource(flags); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 2
+	}
+	played := 0
+	if flags.play != "" {
+		frames, err := playFrames(flags)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
+		}
+		m.v.SetSequence(frames, playEvery(flags))
+		played = len(frames)
+	}
+	m.runFor = flags.runLimit(s, played, playEvery(flags))
+	if flags.art != "" {
+		art, err := loadArt(flags.art)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error: art:", err)
+			return 2
+		}
+		m.piped = &art
+		m.showPicture()
+	}
+	if flags.image != "" {
+		if err := m.openImage(flags.image); err != nil {
+			fmt.Fprintln(os.Stderr, "error: image:", err)
+			return 2
+		}
 	}
-	return b
-}
 
-func main() {
-	p := tea.NewProgram(newModel(), tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithoutCatchPanics())
+	crashProgram.Store(p)
+	defer recoverCrash() // Update and View run on this goroutine
+	if flags.control != "" {
+		stop, err := listenControl(flags.control, p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
+		}
+		defer stop()
+	}
+	defer notifySignals(p)()
+	if flags.mqtt != "" {
+		stop, err := subscribeMQTT(flags.mqtt, p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 2
+		}
+		defer stop()
+	}
 	if err := p.Start(); err != nil {
-		fmt.Println("error:", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
 	}
+	return 0
 }
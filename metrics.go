@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Server metrics (/metrics, Prometheus text format)
+//------------------------------------------------------------------------------
+
+// latencyBuckets are the upper bounds, in seconds, of the latency
+// histograms.
+var latencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+
+// latency is a histogram of durations over latencyBuckets.
+type latency struct {
+	buckets []uint64 // cumulative per bound
+	count   uint64
+	sum     float64
+}
+
+func newLatency() latency { return latency{buckets: make([]uint64, len(latencyBuckets))} }
+
+func (h *latency) observe(d time.Duration) {
+	secs := d.Seconds()
+	for i, le := range latencyBuckets {
+		if secs <= le {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += secs
+}
+
+// write prints the histogram as metric name.
+func (h *latency) write(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i, le := range latencyBuckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, le, h.buckets[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// serverMetrics counts what the servers do. The zero value is not ready;
+// use newServerMetrics.
+type serverMetrics struct {
+	mu       sync.Mutex
+	renders  map[string]uint64 // by server
+	fonts    map[string]uint64 // renders by font
+	sessions map[string]int64  // open sessions by server
+	render   latency
+	frame    latency // coloring each frame of the live banner
+}
+
+var metrics = newServerMetrics()
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		renders:  map[string]uint64{},
+		fonts:    map[string]uint64{},
+		sessions: map[string]int64{},
+		render:   newLatency(),
+		frame:    newLatency(),
+	}
+}
+
+// rendered records one render by server in font that took d.
+func (sm *serverMetrics) rendered(server, font string, d time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.renders[server]++
+	sm.fonts[font]++
+	sm.render.observe(d)
+}
+
+// framed records one frame of the live banner that took d to color. Frames
+// aren't renders: the art is composed once per change.
+func (sm *serverMetrics) framed(d time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.frame.observe(d)
+}
+
+// session marks a session opening on server; call the result when it
+// closes.
+func (sm *serverMetrics) session(server string) func() {
+	sm.mu.Lock()
+	sm.sessions[server]++
+	sm.mu.Unlock()
+	return func() {
+		sm.mu.Lock()
+		sm.sessions[server]--
+		sm.mu.Unlock()
+	}
+}
+
+// write prints every metric in the Prometheus text exposition format.
+func (sm *serverMetrics) write(w io.Writer) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP ascii_viewer_renders_total Banners rendered, by server.")
+	fmt.Fprintln(w, "# TYPE ascii_viewer_renders_total counter")
+	for _, k := range sortedKeys(sm.renders) {
+		fmt.Fprintf(w, "ascii_viewer_renders_total{server=%q} %d\n", k, sm.renders[k])
+	}
+
+	fmt.Fprintln(w, "# HELP ascii_viewer_font_renders_total Banners rendered, by font.")
+	fmt.Fprintln(w, "# TYPE ascii_viewer_font_renders_total counter")
+	for _, k := range sortedKeys(sm.fonts) {
+		fmt.Fprintf(w, "ascii_viewer_font_renders_total{font=%q} %d\n", k, sm.fonts[k])
+	}
+
+	sm.render.write(w, "ascii_viewer_render_seconds", "Time to compose and color a banner.")
+	sm.frame.write(w, "ascii_viewer_live_frame_seconds", "Time to color one frame of the live banner.")
+
+	fmt.Fprintln(w, "# HELP ascii_viewer_active_sessions Open sessions, by server.")
+	fmt.Fprintln(w, "# TYPE ascii_viewer_active_sessions gauge")
+	for _, k := range sortedKeys(sm.sessions) {
+		fmt.Fprintf(w, "ascii_viewer_active_sessions{server=%q} %d\n", k, sm.sessions[k])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.write(w)
+}
+
+// listenMetrics serves /metrics on its own address, for the servers that
+// don't speak HTTP. An empty addr does nothing.
+func listenMetrics(addr string) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", serveMetrics)
+	go func() {
+		log.Printf("metrics on %s/metrics", addr)
+		if err := (&http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}).ListenAndServe(); err != nil {
+			log.Printf("metrics: %v", err)
+		}
+	}()
+}
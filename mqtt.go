@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+//------------------------------------------------------------------------------
+// MQTT subscription (--mqtt BROKER/TOPIC)
+//------------------------------------------------------------------------------
+
+// mqttMsg carries a payload that arrived on the subscribed topic.
+type mqttMsg struct{ payload string }
+
+// parseMQTT splits "[scheme://]host[:port]/topic" into a broker URL and a
+// topic, e.g. "localhost/home/display" into "tcp://localhost:1883" and
+// "home/display".
+func parseMQTT(spec string) (broker, topic string, err error) {
+	scheme := "tcp"
+	if s, rest, ok := strings.Cut(spec, "://"); ok {
+		scheme, spec = s, rest
+	}
+	host, topic, ok := strings.Cut(spec, "/")
+	if !ok || host == "" || topic == "" {
+		return "", "", fmt.Errorf("want broker/topic, got %q", spec)
+	}
+	if !strings.Contains(host, ":") {
+		host += ":1883"
+	}
+	return scheme + "://" + host, topic, nil
+}
+
+// subscribeMQTT forwards every payload on the topic to p. The client keeps
+// retrying until the broker answers and reconnects on its own; connection
+// changes show in the status line. The returned func disconnects.
+func subscribeMQTT(spec string, p *tea.Program) (func(), error) {
+	broker, topic, err := parseMQTT(spec)
+	if err != nil {
+		return nil, err
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(fmt.Sprintf("ascii-viewer-%d", os.Getpid())).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(5 * time.Second)
+	// Subscribe in the connect handler so reconnects resubscribe.
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		c.Subscribe(topic, 0, func(_ mqtt.Client, m mqtt.Message) {
+			p.Send(mqttMsg{payload: string(m.Payload())})
+		})
+		p.Send(statusMsg("mqtt: listening on " + topic))
+	})
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		p.Send(statusMsg("mqtt: connection lost: " + err.Error()))
+	})
+	c := mqtt.NewClient(opts)
+	c.Connect()
+	return func() { c.Disconnect(250) }, nil
+}
+
+// handleMQTT shows a payload as the banner text. Payloads FIGlet can't
+// draw are reported instead.
+func (m model) handleMQTT(msg mqttMsg) (model, tea.Cmd) {
+	text := strings.TrimSpace(msg.payload)
+	if err := checkServeText(text); err != nil {
+		m.status = "mqtt: " + err.Error()
+		return m, nil
+	}
+	m.v.SetText(text)
+	m.status = "mqtt: " + text
+	return m, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+//------------------------------------------------------------------------------
+// Output files (render --output)
+//------------------------------------------------------------------------------
+
+// atomicFile is output written to a temporary file beside path and renamed
+// over it when done, so a banner regenerated by cron or a systemd timer is
+// never seen half written.
+type atomicFile struct {
+	*os.File
+	path string
+}
+
+func createAtomic(path string) (*atomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{f, path}, nil
+}
+
+// finish renames the file over path if err, the outcome of writing it, is
+// nil, keeping the mode of any file it replaces; otherwise, or if that
+// fails, it removes the file. It returns err or the error finishing.
+func (f *atomicFile) finish(err error) error {
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		mode := fs.FileMode(0o644) // CreateTemp's is 0600
+		if info, serr := os.Stat(f.path); serr == nil {
+			mode = info.Mode().Perm()
+		}
+		err = os.Chmod(f.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
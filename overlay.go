@@ -0,0 +1,73 @@
+package main
+
+import "net/http"
+
+//------------------------------------------------------------------------------
+// Stream overlay page (/overlay, or / under "serve overlay")
+//------------------------------------------------------------------------------
+
+// overlayPage draws the live banner from /ws on a transparent page, for use
+// as an OBS browser source. ?size=32px sets the font size; the font is a
+// web font with local monospace fallbacks.
+const overlayPage = `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>banner overlay</title>
+<link rel="stylesheet" href="https://fonts.googleapis.com/css2?family=JetBrains+Mono&display=swap">
+<style>
+  html, body { margin: 0; background: transparent; overflow: hidden; }
+  body { display: flex; align-items: center; justify-content: center; height: 100vh; }
+  pre {
+    margin: 0;
+    font-family: "JetBrains Mono", "DejaVu Sans Mono", Menlo, Consolas, monospace;
+    line-height: 1.1;
+  }
+</style>
+</head>
+<body>
+<pre id="banner"></pre>
+<script>
+const pre = document.getElementById("banner");
+pre.style.fontSize = new URLSearchParams(location.search).get("size") || "24px";
+
+function esc(s) {
+  return s.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+}
+
+// One span per run of same-colored cells, as Frame.HTML does.
+function draw(frame) {
+  const rows = frame.rows.map(cells => {
+    let out = "", run = "", fg;
+    const flush = () => {
+      if (run) out += fg ? '<span style="color:' + fg + '">' + esc(run) + "</span>" : esc(run);
+      run = "";
+    };
+    for (const c of cells) {
+      if (c.fg !== fg) { flush(); fg = c.fg; }
+      run += c.rune;
+    }
+    flush();
+    return out;
+  });
+  pre.innerHTML = rows.join("\n");
+}
+
+function connect() {
+  const ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+  ws.onmessage = ev => {
+    const msg = JSON.parse(ev.data);
+    if (msg.frame) draw(msg.frame);
+  };
+  ws.onclose = () => setTimeout(connect, 1000); // server restarts, network blips
+}
+connect();
+</script>
+</body>
+</html>
+`
+
+func serveOverlay(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(overlayPage))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Pre-rendered art (--art FILE, --art -)
+//------------------------------------------------------------------------------
+
+// maxArtBytes caps --art input, in case something endless is piped in.
+const maxArtBytes = 1 << 20
+
+// checkArtSource rejects more than one of --art, --play and --image.
+func checkArtSource(c cliFlags) error {
+	var set []string
+	for _, f := range []struct{ name, value string }{{"--art", c.art}, {"--play", c.play}, {"--image", c.image}} {
+		if f.value != "" {
+			set = append(set, f.name)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("pick one of %s", strings.Join(set, " and "))
+	}
+	return nil
+}
+
+// loadArt reads the art at path, or stdin for "-", for coloring.
+func loadArt(path string) (banner.Art, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(io.LimitReader(os.Stdin, maxArtBytes))
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return banner.Art{}, err
+	}
+	art := banner.ParseArt(string(data))
+	if len(art.Rows) == 0 {
+		return banner.Art{}, errors.New("no art in the input")
+	}
+	return art, nil
+}
@@ -0,0 +1,77 @@
+package banner
+
+import "math"
+
+//------------------------------------------------------------------------------
+// Chromatic aberration
+//------------------------------------------------------------------------------
+
+// Aberration splits the banner's colors like a worn VHS tape: a red-tinted
+// copy Offset cells to the left and a blue-tinted one to the right show
+// where the letters don't cover them. With Jitter the split wobbles from
+// tick to tick. The frame grows by the widest split each side.
+type Aberration struct {
+	Offset int  // cells between the copies and the banner
+	Jitter bool // vary the offset as it animates
+	t      int
+}
+
+var (
+	aberrationRed  = Color{255, 30, 60}
+	aberrationBlue = Color{30, 110, 255}
+)
+
+func NewAberration(offset int, jitter bool) *Aberration {
+	return &Aberration{Offset: max(1, offset), Jitter: jitter}
+}
+
+func (a *Aberration) Init(_, _ int) { a.t = 0 }
+
+func (a *Aberration) Tick() { a.t++ }
+
+// offset is the split this tick: Offset, now and then a cell more or less.
+func (a *Aberration) offset() int {
+	if !a.Jitter {
+		return a.Offset
+	}
+	t := float64(a.t)
+	return max(0, a.Offset+int(math.Round(math.Sin(t*0.7)*math.Sin(t*0.13)*1.4)))
+}
+
+func (a *Aberration) Apply(f Frame) Frame {
+	off, pad := a.offset(), a.Offset+1 // pad fits any jitter, so the size holds
+	ink := func(x, y int) (Cell, bool) {
+		x -= pad
+		if x < 0 || x >= len(f.Cells[y]) {
+			return Cell{}, false
+		}
+		c := f.Cells[y][x]
+		return c, !c.Blank && c.Rune != ' '
+	}
+	w := f.Width + 2*pad
+	for y := range f.Cells {
+		row := make([]Cell, w)
+		for x := range row {
+			c, ok := ink(x, y)
+			if ok {
+				row[x] = c
+				continue
+			}
+			red, fromRed := ink(x+off, y)   // the red copy sits left
+			blue, fromBlue := ink(x-off, y) // and the blue one right
+			switch {
+			case fromRed && fromBlue:
+				row[x] = Cell{Rune: red.Rune, FG: Lerp(Lerp(red.FG, aberrationRed, 0.6), Lerp(blue.FG, aberrationBlue, 0.6), 0.5)}
+			case fromRed:
+				row[x] = Cell{Rune: red.Rune, FG: Lerp(red.FG, aberrationRed, 0.6)}
+			case fromBlue:
+				row[x] = Cell{Rune: blue.Rune, FG: Lerp(blue.FG, aberrationBlue, 0.6)}
+			default:
+				row[x] = Cell{Rune: ' ', Blank: true}
+			}
+		}
+		f.Cells[y] = row
+	}
+	f.Width = w
+	return f
+}
@@ -0,0 +1,118 @@
+package banner
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+//------------------------------------------------------------------------------
+// Animated images (GIF, APNG, WebP)
+//------------------------------------------------------------------------------
+
+// The encoders take frames from AnimationWriter.Capture, rasterize each
+// with Image(scale) and loop forever, showing each frame for delay. APNG
+// and WebP keep full 24-bit color; GIF has a 256-color palette, which bands
+// smooth gradients, and is there for the places that take nothing else.
+
+// GIF encodes frames as an animated GIF, dithered to the web-safe palette.
+func GIF(w io.Writer, frames []Frame, delay time.Duration, scale int) error {
+	pal := append(color.Palette{color.Transparent}, palette.WebSafe...)
+	anim := &gif.GIF{}
+	for _, f := range frames {
+		img := f.Image(scale)
+		p := image.NewPaletted(img.Rect, pal)
+		draw.FloydSteinberg.Draw(p, img.Rect, img, image.Point{})
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond))) // centiseconds
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// WebP encodes frames as a lossless animated WebP.
+func WebP(w io.Writer, frames []Frame, delay time.Duration, scale int) error {
+	anim := &nativewebp.Animation{}
+	for _, f := range frames {
+		anim.Images = append(anim.Images, f.Image(scale))
+		anim.Durations = append(anim.Durations, uint(delay.Milliseconds()))
+		anim.Disposals = append(anim.Disposals, 1) // clear to the transparent background
+	}
+	return nativewebp.EncodeAll(w, anim, nil)
+}
+
+// APNG encodes frames as an animated PNG. Viewers without APNG support
+// show the first frame.
+func APNG(w io.Writer, frames []Frame, delay time.Duration, scale int) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	scale = max(1, scale)
+	pw, ph := uint32(frames[0].Width*scale*RasterCellW), uint32(frames[0].Height*scale*RasterCellH)
+
+	var b bytes.Buffer
+	b.WriteString("\x89PNG\r\n\x1a\n")
+	pngChunk(&b, "IHDR", be32(pw), be32(ph), []byte{8, 6, 0, 0, 0}) // 8-bit RGBA
+	pngChunk(&b, "acTL", be32(uint32(len(frames))), be32(0))        // 0 plays forever
+	seq := uint32(0)
+	for i, f := range frames {
+		data, err := pngData(f.Image(scale))
+		if err != nil {
+			return err
+		}
+		ms := uint16(min(delay.Milliseconds(), 65535))
+		pngChunk(&b, "fcTL", be32(seq), be32(pw), be32(ph), be32(0), be32(0),
+			be16(ms), be16(1000), []byte{1, 0}) // clear to transparent, no blending
+		seq++
+		if i == 0 {
+			pngChunk(&b, "IDAT", data) // doubles as the still image
+			continue
+		}
+		pngChunk(&b, "fdAT", be32(seq), data)
+		seq++
+	}
+	pngChunk(&b, "IEND")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pngData is img's compressed scanlines, unfiltered: every frame has to
+// be RGBA to share the header, which image/png doesn't promise.
+func pngData(img *image.NRGBA) ([]byte, error) {
+	var b bytes.Buffer
+	z := zlib.NewWriter(&b)
+	w := img.Rect.Dx() * 4
+	for y := 0; y < img.Rect.Dy(); y++ {
+		z.Write([]byte{0}) // filter type: none
+		z.Write(img.Pix[y*img.Stride : y*img.Stride+w])
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// pngChunk writes a chunk of the given type whose data is parts joined.
+func pngChunk(b *bytes.Buffer, typ string, parts ...[]byte) {
+	data := bytes.Join(parts, nil)
+	b.Write(be32(uint32(len(data))))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	b.WriteString(typ)
+	b.Write(data)
+	b.Write(be32(crc.Sum32()))
+}
+
+func be32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+func be16(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
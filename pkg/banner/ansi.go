@@ -0,0 +1,97 @@
+package banner
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/muesli/termenv"
+)
+
+//------------------------------------------------------------------------------
+// ANSI encoding
+//------------------------------------------------------------------------------
+
+// Frames are encoded straight to SGR sequences rather than through
+// lipgloss styles: one escape per run of same-colored cells instead of one
+// per cell, into a pooled buffer.
+
+const sgrReset = "\x1b[0m"
+
+var bufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// sgrCache holds the foreground sequence per profile and color. Animation
+// keeps producing new colors, so the cache is dropped when it grows past
+// sgrCacheMax rather than kept forever.
+var sgrCache = struct {
+	sync.Mutex
+	m map[sgrKey]string
+}{m: map[sgrKey]string{}}
+
+const sgrCacheMax = 4096
+
+type sgrKey struct {
+	p termenv.Profile
+	c Color
+}
+
+// sgr returns the escape selecting c as foreground in p, or "" when p has
+// no colors or c is not a valid color.
+func sgr(p termenv.Profile, c Color) string {
+	if p == termenv.Ascii {
+		return ""
+	}
+	k := sgrKey{p, c}
+	sgrCache.Lock()
+	defer sgrCache.Unlock()
+	s, ok := sgrCache.m[k]
+	if !ok {
+		if len(sgrCache.m) >= sgrCacheMax {
+			clear(sgrCache.m)
+		}
+		if col := p.Color(c.Hex()); col != nil { // nil for a color out of range
+			if seq := col.Sequence(false); seq != "" {
+				s = "\x1b[" + seq + "m"
+			}
+		}
+		sgrCache.m[k] = s
+	}
+	return s
+}
+
+// encode writes the frame for profile p, rows separated by newlines. Each
+// colored run is closed with a reset, so rows are self-contained and
+// blank cells always show the terminal's own colors.
+func (f Frame) encode(p termenv.Profile) string {
+	b := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(b)
+	b.Reset()
+	cw := f.cellWidth()
+	b.Grow(f.Height * (f.Width*cw + 1) * 4) // a glyph plus some escape per cell
+	for y, cells := range f.Cells {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		open := "" // sequence of the run being written
+		for _, c := range cells {
+			seq := ""
+			if !c.Blank {
+				seq = sgr(p, c.FG)
+			}
+			if seq != open {
+				if open != "" {
+					b.WriteString(sgrReset)
+				}
+				b.WriteString(seq)
+				open = seq
+			}
+			b.WriteRune(c.Rune)
+			if cw > 1 && (c.Blank || RuneWidth(c.Rune) == 1) {
+				b.WriteByte(' ')
+			}
+		}
+		if open != "" {
+			b.WriteString(sgrReset)
+		}
+	}
+	return b.String()
+}
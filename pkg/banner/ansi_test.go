@@ -0,0 +1,16 @@
+package banner
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestSGRInvalidColor(t *testing.T) {
+	if got := sgr(termenv.TrueColor, Color{R: 1000, G: -5}); got != "" {
+		t.Errorf("sgr of an invalid color = %q, want no sequence", got)
+	}
+	if got := sgr(termenv.TrueColor, Color{R: 255}); got != "\x1b[38;2;255;0;0m" {
+		t.Errorf("sgr(#ff0000) = %q", got)
+	}
+}
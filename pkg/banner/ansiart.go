@@ -0,0 +1,342 @@
+package banner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/text/encoding/charmap"
+)
+
+//------------------------------------------------------------------------------
+// Reading .ans and .nfo files
+//------------------------------------------------------------------------------
+
+// Screen is ANSI art played onto a grid, as a DOS terminal would show it.
+type Screen struct {
+	Width int
+	Rows  [][]ScreenCell
+	Sauce *Sauce // the file's SAUCE record, if it has one
+}
+
+// ScreenCell is one character of a Screen. Unlike a Frame's cells, these
+// have a background; black backgrounds are left to the terminal.
+type ScreenCell struct {
+	Rune   rune
+	FG, BG Color
+}
+
+// dosPalette is the 16 CGA colors in SGR order: black, red, green, brown,
+// blue, magenta, cyan, gray, then their bright forms.
+var dosPalette = [16]Color{
+	{0, 0, 0}, {170, 0, 0}, {0, 170, 0}, {170, 85, 0}, {0, 0, 170}, {170, 0, 170}, {0, 170, 170}, {170, 170, 170},
+	{85, 85, 85}, {255, 85, 85}, {85, 255, 85}, {255, 255, 85}, {85, 85, 255}, {255, 85, 255}, {85, 255, 255}, {255, 255, 255},
+}
+
+// ansWidth is where lines wrap when the file doesn't say.
+const ansWidth = 80
+
+// The largest screen a file may draw; cells past it are dropped, so a
+// cursor sent to row 50000000 can't make the screen that big.
+const (
+	ansMaxRows = 4000
+	ansMaxCols = 512
+)
+
+// ParseANS plays an .ans or .nfo file: code page 437 text with SGR colors
+// and cursor movement. Lines wrap at the SAUCE width, or 80 columns, as in
+// a DOS terminal; files without escapes (.nfo) don't wrap. Bold brightens
+// the foreground and blink the background (iCE colors).
+func ParseANS(data []byte) Screen {
+	var s Screen
+	data, s.Sauce, s.Width = readSauce(data)
+	if s.Width == 0 {
+		s.Width = ansWidth
+	}
+	s.Width = min(s.Width, ansMaxCols)
+	wrap := bytes.IndexByte(data, 0x1b) >= 0
+
+	p := ansPlayer{screen: &s, wrap: wrap}
+	p.reset()
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; c {
+		case 0x1a:
+			i = len(data) // DOS end of file
+		case '\r':
+			p.x = 0
+		case '\n':
+			p.x, p.y = 0, p.y+1
+		case '\t':
+			p.x = (p.x/8 + 1) * 8
+		case 0x1b:
+			if i+1 < len(data) && data[i+1] == '[' {
+				end := i + 2
+				for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+					end++
+				}
+				if end == len(data) {
+					i = end
+					break
+				}
+				p.csi(string(data[i+2:end]), data[end])
+				i = end
+			}
+		default:
+			if c < ' ' {
+				break // other controls draw nothing
+			}
+			p.put(charmap.CodePage437.DecodeByte(c))
+		}
+	}
+	if !wrap {
+		for _, row := range s.Rows {
+			s.Width = max(s.Width, len(row))
+		}
+	}
+	return s
+}
+
+// ansPlayer is the cursor and pen while a Screen is played.
+type ansPlayer struct {
+	screen       *Screen
+	wrap         bool
+	x, y         int
+	savedX       int
+	savedY       int
+	fg, bg       int // dosPalette indexes
+	bold, blink  bool
+	fgRGB, bgRGB *Color // 256-color and truecolor pens
+}
+
+func (p *ansPlayer) reset() {
+	p.fg, p.bg, p.bold, p.blink, p.fgRGB, p.bgRGB = 7, 0, false, false, nil, nil
+}
+
+func (p *ansPlayer) put(r rune) {
+	if p.wrap && p.x >= p.screen.Width {
+		p.x, p.y = 0, p.y+1
+	}
+	if p.y >= ansMaxRows || p.x >= ansMaxCols {
+		p.x++
+		return
+	}
+	for len(p.screen.Rows) <= p.y {
+		p.screen.Rows = append(p.screen.Rows, nil)
+	}
+	row := p.screen.Rows[p.y]
+	for len(row) <= p.x {
+		row = append(row, ScreenCell{Rune: ' ', FG: dosPalette[7]})
+	}
+	c := ScreenCell{Rune: r, FG: dosPalette[p.fg], BG: dosPalette[p.bg]}
+	if p.bold && p.fg < 8 {
+		c.FG = dosPalette[p.fg+8]
+	}
+	if p.blink && p.bg < 8 {
+		c.BG = dosPalette[p.bg+8]
+	}
+	if p.fgRGB != nil {
+		c.FG = *p.fgRGB
+	}
+	if p.bgRGB != nil {
+		c.BG = *p.bgRGB
+	}
+	row[p.x] = c
+	p.screen.Rows[p.y] = row
+	p.x++
+}
+
+// csi runs one control sequence with its parameters.
+func (p *ansPlayer) csi(params string, final byte) {
+	var n []int
+	for _, f := range strings.Split(strings.TrimLeft(params, "?"), ";") {
+		v, _ := strconv.Atoi(f)
+		n = append(n, v)
+	}
+	arg := func(i, def int) int {
+		if i < len(n) && n[i] > 0 {
+			return n[i]
+		}
+		return def
+	}
+	switch final {
+	case 'm':
+		p.sgr(n)
+	case 'A':
+		p.y = max(0, p.y-arg(0, 1))
+	case 'B':
+		p.y += arg(0, 1)
+	case 'C':
+		p.x += arg(0, 1)
+		if p.wrap {
+			p.x = min(p.x, p.screen.Width) // cursor stops at the margin
+		}
+	case 'D':
+		p.x = max(0, p.x-arg(0, 1))
+	case 'H', 'f':
+		p.y, p.x = arg(0, 1)-1, arg(1, 1)-1
+	case 'J':
+		if arg(0, 0) == 2 {
+			p.screen.Rows, p.x, p.y = nil, 0, 0
+		}
+	case 'K':
+		if p.y < len(p.screen.Rows) && p.x < len(p.screen.Rows[p.y]) {
+			p.screen.Rows[p.y] = p.screen.Rows[p.y][:p.x]
+		}
+	case 's':
+		p.savedX, p.savedY = p.x, p.y
+	case 'u':
+		p.x, p.y = p.savedX, p.savedY
+	}
+}
+
+func (p *ansPlayer) sgr(n []int) {
+	for i := 0; i < len(n); i++ {
+		switch v := n[i]; {
+		case v == 0:
+			p.reset()
+		case v == 1:
+			p.bold = true
+		case v == 5:
+			p.blink = true
+		case v == 22:
+			p.bold = false
+		case v == 25:
+			p.blink = false
+		case v >= 30 && v <= 37:
+			p.fg, p.fgRGB = v-30, nil
+		case v == 39:
+			p.fg, p.fgRGB = 7, nil
+		case v >= 40 && v <= 47:
+			p.bg, p.bgRGB = v-40, nil
+		case v == 49:
+			p.bg, p.bgRGB = 0, nil
+		case v >= 90 && v <= 97:
+			p.fg, p.fgRGB = v-90+8, nil
+		case v >= 100 && v <= 107:
+			p.bg, p.bgRGB = v-100+8, nil
+		case v == 38 || v == 48:
+			c, used := extendedColor(n[i+1:])
+			i += used
+			if v == 38 {
+				p.fgRGB = c
+			} else {
+				p.bgRGB = c
+			}
+		}
+	}
+}
+
+// extendedColor reads the "5;N" or "2;R;G;B" after a 38 or 48, returning
+// the color (nil if malformed) and how many parameters it took.
+func extendedColor(n []int) (*Color, int) {
+	switch {
+	case len(n) >= 2 && n[0] == 5:
+		c := xterm256(n[1])
+		return &c, 2
+	case len(n) >= 4 && n[0] == 2:
+		channel := func(v int) int { return min(max(v, 0), 255) }
+		return &Color{channel(n[1]), channel(n[2]), channel(n[3])}, 4
+	}
+	return nil, len(n)
+}
+
+// xterm256 is color i of the xterm 256-color palette.
+func xterm256(i int) Color {
+	switch {
+	case i < 16:
+		return dosPalette[max(0, i)]
+	case i < 232:
+		i -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + 40*v
+		}
+		return Color{level(i / 36), level(i / 6 % 6), level(i % 6)}
+	default:
+		g := 8 + 10*(min(i, 255)-232)
+		return Color{g, g, g}
+	}
+}
+
+// readSauce splits a trailing SAUCE record off data, returning the art,
+// the record and the width it gives (0 if none).
+func readSauce(data []byte) ([]byte, *Sauce, int) {
+	const size = 128
+	if len(data) < size || !bytes.HasPrefix(data[len(data)-size:], []byte("SAUCE00")) {
+		return data, nil, 0
+	}
+	rec := data[len(data)-size:]
+	field := func(from, n int) string { return strings.TrimRight(string(rec[from:from+n]), " \x00") }
+	s := &Sauce{Title: field(7, 35), Author: field(42, 20), Group: field(62, 20)}
+	s.Date, _ = time.Parse("20060102", field(82, 8))
+	width := 0
+	if rec[94] == 1 { // character data: TInfo1 is the width
+		width = int(binary.LittleEndian.Uint16(rec[96:]))
+	}
+	return data[:len(data)-size], s, width
+}
+
+// Lines encodes rows [from, from+n) of the screen for r's color profile.
+func (s Screen) Lines(r *lipgloss.Renderer, from, n int) []string {
+	profile := r.ColorProfile()
+	var lines []string
+	for y := max(0, from); y < min(len(s.Rows), from+n); y++ {
+		lines = append(lines, s.encodeRow(profile, s.Rows[y]))
+	}
+	return lines
+}
+
+// ANSI encodes the whole screen with truecolor escapes.
+func (s Screen) ANSI() string {
+	lines := make([]string, len(s.Rows))
+	for y, row := range s.Rows {
+		lines[y] = s.encodeRow(termenv.TrueColor, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s Screen) encodeRow(profile termenv.Profile, row []ScreenCell) string {
+	var b strings.Builder
+	open := ""
+	for _, c := range row {
+		seq := ""
+		if c.Rune != ' ' || c.BG != dosPalette[0] {
+			seq = sgr(profile, c.FG)
+		}
+		if c.BG != dosPalette[0] {
+			seq += bgSGR(profile, c.BG)
+		}
+		if seq != open {
+			if open != "" {
+				b.WriteString(sgrReset)
+			}
+			b.WriteString(seq)
+			open = seq
+		}
+		b.WriteRune(c.Rune)
+	}
+	if open != "" {
+		b.WriteString(sgrReset)
+	}
+	return b.String()
+}
+
+// Art is the screen's characters without their colors, to color like a
+// banner.
+func (s Screen) Art() Art {
+	lines := make([]string, len(s.Rows))
+	for y, row := range s.Rows {
+		rs := make([]rune, len(row))
+		for x, c := range row {
+			rs[x] = c.Rune
+		}
+		lines[y] = strings.TrimRight(string(rs), " ")
+	}
+	return newArt(lines)
+}
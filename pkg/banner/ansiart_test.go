@@ -0,0 +1,155 @@
+package banner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// screenText is s's runes, a line per row.
+func screenText(s Screen) []string {
+	lines := make([]string, len(s.Rows))
+	for y, row := range s.Rows {
+		var b strings.Builder
+		for _, c := range row {
+			b.WriteRune(c.Rune)
+		}
+		lines[y] = b.String()
+	}
+	return lines
+}
+
+func TestParseANS(t *testing.T) {
+	tests := []struct {
+		name, data string
+		want       []string
+	}{
+		{"nfo lines", "ab\r\ncd", []string{"ab", "cd"}},
+		{"cp437", "\xdb\xb0\xfa", []string{"█░·"}},
+		{"tab", "a\tb", []string{"a       b"}},
+		{"controls", "a\x07b", []string{"ab"}},
+		{"eof", "ab\x1acd", []string{"ab"}},
+		{"position", "\x1b[2;3Hx\x1b[1;1Hy", []string{"y", "  x"}},
+		{"relative", "a\x1b[Bb\x1b[2Dc\x1b[Ad\x1b[3Ce", []string{"ad   e", "cb"}},
+		{"save and restore", "a\x1b[s\x1b[3;1Hb\x1b[uc", []string{"ac", "", "b"}},
+		{"clear", "ab\x1b[2Jc", []string{"c"}},
+		{"erase line", "abcd\x1b[1;3H\x1b[K", []string{"ab"}},
+		{"wrap at 80", "\x1b[0m" + strings.Repeat("x", 81), []string{strings.Repeat("x", 80), "x"}},
+		{"margin", "\x1b[100Cx", []string{"", "x"}},
+		{"unterminated escape", "a\x1b[1;", []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := screenText(ParseANS([]byte(tt.data))); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseANSColors(t *testing.T) {
+	tests := []struct {
+		name, data string
+		fg, bg     Color
+	}{
+		{"default", "x", dosPalette[7], dosPalette[0]},
+		{"sgr", "\x1b[31;44mx", dosPalette[1], dosPalette[4]},
+		{"bold", "\x1b[1;32mx", dosPalette[10], dosPalette[0]},
+		{"ice blink", "\x1b[5;41mx", dosPalette[7], dosPalette[9]},
+		{"bright", "\x1b[93;104mx", dosPalette[11], dosPalette[12]},
+		{"reset", "\x1b[31;44m\x1b[0mx", dosPalette[7], dosPalette[0]},
+		{"default colors", "\x1b[31;44m\x1b[39;49mx", dosPalette[7], dosPalette[0]},
+		{"256", "\x1b[38;5;196;48;5;232mx", Color{255, 0, 0}, Color{8, 8, 8}},
+		{"truecolor", "\x1b[38;2;1;2;3mx", Color{1, 2, 3}, dosPalette[0]},
+		{"truecolor over bold", "\x1b[1;38;2;1;2;3mx", Color{1, 2, 3}, dosPalette[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ParseANS([]byte(tt.data)).Rows[0][0]
+			if c.FG != tt.fg || c.BG != tt.bg {
+				t.Errorf("got %v on %v, want %v on %v", c.FG, c.BG, tt.fg, tt.bg)
+			}
+		})
+	}
+}
+
+// TestSauceLayout checks the record ANS writes field by field against the
+// SAUCE 00 layout, and that ParseANS reads it back.
+func TestSauceLayout(t *testing.T) {
+	f, err := Render("Hi", WithFont("standard"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Sauce{Title: "A title", Author: "someone with a long name", Group: "gr\x01p", Date: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)}
+	data := f.ANS(s)
+	art := f.ANS(nil)
+	if len(data) != len(art)+1+128 || data[len(art)] != 0x1a {
+		t.Fatalf("%d bytes, want the %d of the art, EOF and a 128-byte record", len(data), len(art))
+	}
+	rec := data[len(data)-128:]
+	le16 := func(i int) int { return int(rec[i]) | int(rec[i+1])<<8 }
+	fields := []struct {
+		name     string
+		from, to int
+		want     string
+	}{
+		{"id", 0, 7, "SAUCE00"},
+		{"title", 7, 42, "A title" + strings.Repeat(" ", 28)},
+		{"author", 42, 62, "someone with a long "},
+		{"group", 62, 82, "gr?p" + strings.Repeat(" ", 16)},
+		{"date", 82, 90, "20240309"},
+		{"data and file type", 94, 96, "\x01\x01"},
+		{"comments, flags and font", 104, 128, strings.Repeat("\x00", 24)},
+	}
+	for _, fl := range fields {
+		if got := string(rec[fl.from:fl.to]); got != fl.want {
+			t.Errorf("%s = %q, want %q", fl.name, got, fl.want)
+		}
+	}
+	size := int(rec[90]) | int(rec[91])<<8 | int(rec[92])<<16 | int(rec[93])<<24
+	if size != len(art) || le16(96) != f.Width || le16(98) != f.Height {
+		t.Errorf("size %d, %dx%d; want %d, %dx%d", size, le16(96), le16(98), len(art), f.Width, f.Height)
+	}
+
+	screen := ParseANS(data)
+	if screen.Sauce == nil || screen.Sauce.Title != "A title" || screen.Sauce.Author != "someone with a long" || !screen.Sauce.Date.Equal(s.Date) {
+		t.Errorf("read back %+v", screen.Sauce)
+	}
+	if screen.Width != f.Width || len(screen.Rows) != f.Height {
+		t.Errorf("read back %dx%d, want %dx%d", screen.Width, len(screen.Rows), f.Width, f.Height)
+	}
+}
+
+func TestParseANSBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"far row", "\x1b[50000000;1Hx"},
+		{"far column", "\x1b[1;50000000Hx"},
+		{"far down", "\x1b[50000000Bx"},
+		{"far right, no wrap", "\x1b[50000000Cx\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ParseANS([]byte(tt.data))
+			if len(s.Rows) > ansMaxRows || s.Width > ansMaxCols {
+				t.Fatalf("screen is %d rows by %d columns", len(s.Rows), s.Width)
+			}
+			for _, row := range s.Rows {
+				if len(row) > ansMaxCols {
+					t.Fatalf("row of %d cells", len(row))
+				}
+			}
+		})
+	}
+}
+
+func TestParseANSTruecolorClamped(t *testing.T) {
+	s := ParseANS([]byte("\x1b[38;2;300;-1;128;48;2;999;0;0mA"))
+	c := s.Rows[0][0]
+	if c.FG != (Color{255, 0, 128}) || c.BG != (Color{255, 0, 0}) {
+		t.Errorf("colors %v on %v, want channels clamped to 0-255", c.FG, c.BG)
+	}
+}
@@ -0,0 +1,353 @@
+// Package banner renders FIGlet text as gradient-colored terminal art. It is
+// the rendering core of the ASCII viewer, usable from any Go program:
+//
+//	f, err := banner.Render("hello",
+//		banner.WithFont("doom"),
+//		banner.WithGradient(banner.Color{138, 43, 226}, banner.Color{0, 255, 255}),
+//		banner.WithMode(banner.ModeBlock),
+//	)
+//	fmt.Println(f)
+//
+// Anything not set keeps the value from DefaultOptions.
+package banner
+
+import (
+	"image"
+	"slices"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+//------------------------------------------------------------------------------
+// Composition
+//------------------------------------------------------------------------------
+
+// Art is uncolored FIGlet output, padded to a common width. Composing is the
+// expensive step, so callers that recolor every frame should keep the Art.
+type Art struct {
+	Rows  [][]rune
+	Width int
+	Marks []Mark // word colors; see WithWordColors
+}
+
+// Compose renders text in the font (built-in or registered), letter
+// spacing, transforms and bubble selected by opts, marking the words of
+// opts.Words; other coloring options are ignored.
+// Text is passed through Sanitize and Normalize first; what remains must be
+// printable ASCII.
+func Compose(text string, opts ...Option) (Art, error) {
+	o := NewOptions(opts...)
+	text, _ = Sanitize(text)
+	text = Normalize(text, o.Normalize)
+	if err := checkDrawable(text); err != nil {
+		return Art{}, err
+	}
+	font := o.Font
+	if font == "" {
+		font = "standard"
+	}
+	runes := []rune(text)
+	if o.Spacing == 0 {
+		lines, err := figureLines(text, font)
+		if err != nil {
+			return Art{}, err
+		}
+		a := newArt(lines)
+		// Letters run together, so a word's columns are found by composing
+		// the text up to either end of it.
+		widthOf := func(n int) (int, error) {
+			if n == 0 {
+				return 0, nil
+			}
+			lines, err := figureLines(string(runes[:n]), font)
+			return newArt(lines).Width, err
+		}
+		a.Marks, err = wordMarks(runes, o.Words, a.height(), func(i, j int) (int, int, error) {
+			from, err := widthOf(i)
+			if err != nil {
+				return 0, 0, err
+			}
+			to, err := widthOf(j)
+			return from, to, err
+		})
+		if err != nil {
+			return Art{}, err
+		}
+		return a.finish(o)
+	}
+
+	// Spaced: compose letters one by one and join them with blank columns.
+	var a Art
+	starts := make([]int, len(runes)+1) // column each letter begins at
+	for i, r := range runes {
+		lines, err := figureLines(string(r), font)
+		if err != nil {
+			return Art{}, err
+		}
+		letter := newArt(lines)
+		if i > 0 {
+			a = a.join(Art{Rows: make([][]rune, letter.height()), Width: o.Spacing})
+		}
+		starts[i] = a.Width
+		a = a.join(letter)
+	}
+	starts[len(runes)] = a.Width + o.Spacing
+	var err error
+	a.Marks, err = wordMarks(runes, o.Words, a.height(), func(i, j int) (int, int, error) {
+		return starts[i], starts[j] - o.Spacing, nil
+	})
+	if err != nil {
+		return Art{}, err
+	}
+	return a.finish(o)
+}
+
+// finish bends, skews and tilts composed art as o says, then wraps it in
+// the bubble. Word marks follow a warp and the bubble, but are dropped by
+// skew and perspective, which move each row's columns differently.
+func (a Art) finish(o Options) (Art, error) {
+	marks := a.Marks
+	a, err := a.Transformed(o)
+	if err != nil {
+		return Art{}, err
+	}
+	if o.Skew != 0 || o.Perspective != 0 {
+		marks = nil
+	}
+	for i := range marks {
+		marks[i].Rect.Max.Y = a.height() // columns shift up and down in a warp
+	}
+	a, err = a.bubble(o.Bubble)
+	if err != nil {
+		return Art{}, err
+	}
+	if o.Bubble.Style != "" {
+		for i := range marks {
+			marks[i].Rect = marks[i].Rect.Add(image.Pt(2, 1)) // the border, a space and the top line
+		}
+	}
+	a.Marks = marks
+	return a, nil
+}
+
+// Transformed applies o's warp, skew and perspective to a, in that order,
+// as Compose does; for art from elsewhere, such as ImageArt.
+func (a Art) Transformed(o Options) (Art, error) {
+	a, err := a.Warped(o.Warp)
+	if err != nil {
+		return Art{}, err
+	}
+	return a.Skewed(o.Skew).Perspective(o.Perspective), nil
+}
+
+func newArt(lines []string) Art {
+	a := Art{Rows: make([][]rune, len(lines))}
+	for y, l := range lines {
+		a.Rows[y] = []rune(l)
+		a.Width = max(a.Width, len(a.Rows[y]))
+	}
+	a.pad()
+	return a
+}
+
+func (a Art) height() int { return len(a.Rows) }
+
+// pad extends every row with spaces to the art width.
+func (a Art) pad() {
+	for y, r := range a.Rows {
+		for len(r) < a.Width {
+			r = append(r, ' ')
+		}
+		a.Rows[y] = r
+	}
+}
+
+// join places b to the right of a, top-aligned. FIGlet output only drops
+// empty rows at the bottom, so top alignment keeps baselines level.
+func (a Art) join(b Art) Art {
+	h := max(a.height(), b.height())
+	out := Art{Rows: make([][]rune, h), Width: a.Width + b.Width}
+	for y := range out.Rows {
+		row := make([]rune, 0, out.Width)
+		if y < a.height() {
+			row = append(row, a.Rows[y]...)
+		}
+		for len(row) < a.Width {
+			row = append(row, ' ')
+		}
+		if y < b.height() {
+			row = append(row, b.Rows[y]...)
+		}
+		out.Rows[y] = row
+	}
+	out.pad()
+	return out
+}
+
+//------------------------------------------------------------------------------
+// Coloring
+//------------------------------------------------------------------------------
+
+// Options is the resolved form of a set of Option values. Build one with
+// NewOptions; the zero value colors everything black.
+type Options struct {
+	Font        string        // FIGlet font; "" means "standard"
+	Spacing     int           // blank columns between letters
+	Start, End  Color         // horizontal gradient endpoints
+	Stops       []Stop        // colors between them, in position order
+	Mode        RenderMode    // fill style for non-space glyphs; nil means ModeGlyph
+	HueShift    float64       // degrees to rotate both gradient endpoints
+	Effects     []Effect      // applied in order by Render
+	Normalize   Normalization // Unicode form text is brought to first
+	Bubble      Bubble        // cowsay-style bubble around the art
+	Warp        Warp          // curve the art is bent along
+	Skew        float64       // columns each row leans right of the one below
+	Perspective float64       // how much the top (> 0) or bottom (< 0) is narrowed
+	Words       []WordColor   // words colored apart from the gradient
+}
+
+// Cell is one character position in a Frame. Blank cells are uncolored
+// spaces.
+type Cell struct {
+	Rune  rune
+	FG    Color
+	Blank bool
+	Fixed bool // a word color, which hue cycling leaves alone
+}
+
+// Frame is colored art, ready to print.
+type Frame struct {
+	Cells  [][]Cell
+	Width  int
+	Height int
+}
+
+// Colorize applies a per-column gradient and render mode to a. Marked
+// words take their own color, unrotated by opts.HueShift.
+func (a Art) Colorize(opts Options) Frame {
+	var f Frame
+	a.ColorizeInto(&f, opts)
+	return f
+}
+
+// ColorizeInto is Colorize writing into f, reusing its cell storage when it
+// is large enough. Animation loops keep one Frame for this; f must not be
+// in use elsewhere.
+func (a Art) ColorizeInto(f *Frame, opts Options) {
+	start, end, stops := opts.Start, opts.End, opts.Stops
+	if opts.HueShift != 0 {
+		start = RotateHue(start, opts.HueShift)
+		end = RotateHue(end, opts.HueShift)
+		stops = rotateStops(stops, opts.HueShift)
+	}
+	mode := opts.Mode
+	if mode == nil {
+		mode = ModeGlyph
+	}
+	cols := gradient(a.Width, start, end, stops)
+	f.Width, f.Height = a.Width, len(a.Rows)
+	f.Cells = resize(f.Cells, len(a.Rows))
+	for y, row := range a.Rows {
+		cells := resize(f.Cells[y], a.Width)
+		for x, ch := range row {
+			if ch == ' ' {
+				cells[x] = Cell{Rune: ' ', Blank: true}
+				continue
+			}
+			c, fixed := a.markedColor(x, y, cols[x])
+			if _, ok := mode.(glyphMode); ok { // skip a string per cell
+				cells[x] = Cell{Rune: ch, FG: c, Fixed: fixed}
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(mode.Cell(x, y, ch, c))
+			if size == 0 {
+				cells[x] = Cell{Rune: ' ', Blank: true}
+				continue
+			}
+			cells[x] = Cell{Rune: r, FG: c, Fixed: fixed}
+		}
+		f.Cells[y] = cells
+	}
+}
+
+// resize returns s with length n, reusing its array when it is big enough.
+func resize[T any](s []T, n int) []T {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]T, n)
+}
+
+// gradientCache remembers the last column gradient: animation recolors the
+// same art with the same endpoints every frame.
+var gradientCache struct {
+	sync.Mutex
+	width      int
+	start, end Color
+	stops      []Stop
+	cols       []Color // shared; never modified after it is cached
+}
+
+// gradient returns the color of each of width columns, start through stops
+// to end.
+func gradient(width int, start, end Color, stops []Stop) []Color {
+	g := &gradientCache
+	g.Lock()
+	defer g.Unlock()
+	if g.cols != nil && g.width == width && g.start == start && g.end == end && slices.Equal(g.stops, stops) {
+		return g.cols
+	}
+	cols := make([]Color, width)
+	for x := range cols {
+		t := 0.0
+		if width > 1 {
+			t = float64(x) / float64(width-1)
+		}
+		cols[x] = Blend(start, end, stops, t)
+	}
+	g.width, g.start, g.end, g.stops, g.cols = width, start, end, slices.Clone(stops), cols
+	return cols
+}
+
+// Render composes and colors text in one step.
+func Render(text string, opts ...Option) (Frame, error) {
+	o := NewOptions(opts...)
+	a, err := Compose(text, WithOptions(o))
+	if err != nil {
+		return Frame{}, err
+	}
+	for _, e := range o.Effects {
+		e.Init(a.Width, a.height())
+	}
+	return ApplyEffects(a.Colorize(o), o.Effects), nil
+}
+
+// String renders the frame with ANSI colors, rows separated by newlines.
+// Colors follow the terminal's detected color profile.
+func (f Frame) String() string { return f.Styled(lipgloss.DefaultRenderer()) }
+
+// Styled renders the frame for r's color profile, e.g. a renderer bound to
+// a remote session.
+func (f Frame) Styled(r *lipgloss.Renderer) string { return f.encode(r.ColorProfile()) }
+
+// Place centers the frame on a canvas width terminal columns by height rows
+// of blank cells. A frame larger than the canvas is returned unchanged in
+// that dimension.
+func (f Frame) Place(width, height int) Frame {
+	w, h := max(width/f.cellWidth(), f.Width), max(height, f.Height)
+	left, top := (w-f.Width)/2, (h-f.Height)/2
+	out := Frame{Cells: make([][]Cell, h), Width: w, Height: h}
+	for y := range out.Cells {
+		row := make([]Cell, w)
+		for x := range row {
+			row[x] = Cell{Rune: ' ', Blank: true}
+		}
+		if sy := y - top; sy >= 0 && sy < f.Height {
+			copy(row[left:], f.Cells[sy])
+		}
+		out.Cells[y] = row
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+// Package bannertest helps tests assert exact banner output against golden
+// files. Run tests with -update to (re)write the files:
+//
+//	out, _ := banner.Headless("hi", 80, 24, banner.WithFont("doom"))
+//	bannertest.Golden(t, "hi", out)
+package bannertest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// Dir is where golden files live, relative to the test's package.
+var Dir = "testdata"
+
+// Golden compares got with Dir/name.golden, failing t with a line diff on
+// mismatch. With -update it writes got instead.
+func Golden(t testing.TB, name string, got string) {
+	t.Helper()
+	path := filepath.Join(Dir, name+".golden")
+	if *update {
+		if err := os.MkdirAll(Dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(want, []byte(got)) {
+		t.Errorf("%s differs from golden file:\n%s", name, diff(string(want), got))
+	}
+}
+
+// diff lists the first few lines that differ, with escapes made visible.
+func diff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < max(len(w), len(g)) && shown < 5; i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl == gl {
+			continue
+		}
+		shown++
+		b.WriteString("line " + strconv.Itoa(i+1) + ":\n  want: " + visible(wl) + "\n  got:  " + visible(gl) + "\n")
+	}
+	if len(w) != len(g) {
+		b.WriteString("want " + strconv.Itoa(len(w)) + " lines, got " + strconv.Itoa(len(g)) + "\n")
+	}
+	return b.String()
+}
+
+func visible(s string) string { return strings.ReplaceAll(s, "\x1b", `\e`) }
@@ -0,0 +1,76 @@
+package banner
+
+import (
+	"io"
+	"testing"
+)
+
+// The render path stage by stage, for benchstat comparisons:
+//
+//	go test -run XXX -bench . -count 10 ./pkg/banner > old.txt
+
+const benchText = "glam dm"
+
+func benchOptions() Options {
+	return Options{Font: "standard", Start: Color{138, 43, 226}, End: Color{0, 255, 255}, Mode: ModeGlyph}
+}
+
+func benchArt(b *testing.B) Art {
+	art, err := Compose(benchText, WithOptions(benchOptions()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return art
+}
+
+func BenchmarkCompose(b *testing.B) {
+	o := benchOptions()
+	b.ReportAllocs()
+	for b.Loop() {
+		Compose(benchText, WithOptions(o))
+	}
+}
+
+func BenchmarkColorize(b *testing.B) {
+	art, o := benchArt(b), benchOptions()
+	b.ReportAllocs()
+	for b.Loop() {
+		art.Colorize(o)
+	}
+}
+
+func BenchmarkColorizeInto(b *testing.B) {
+	art, o := benchArt(b), benchOptions()
+	var dst Frame
+	b.ReportAllocs()
+	for b.Loop() {
+		art.ColorizeInto(&dst, o)
+	}
+}
+
+func BenchmarkEffects(b *testing.B) {
+	art, o := benchArt(b), benchOptions()
+	effects := []Effect{NewHueCycle(6)}
+	var dst Frame
+	b.ReportAllocs()
+	for b.Loop() {
+		art.ColorizeInto(&dst, o)
+		ApplyEffects(dst, effects)
+	}
+}
+
+func BenchmarkANSI(b *testing.B) {
+	f := benchArt(b).Colorize(benchOptions())
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = f.ANSI()
+	}
+}
+
+func BenchmarkAnimationFrame(b *testing.B) {
+	aw := NewAnimationWriter(io.Discard, benchArt(b), WithOptions(benchOptions()), WithEffect(NewHueCycle(6)))
+	b.ReportAllocs()
+	for b.Loop() {
+		aw.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package banner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Speech bubbles (cowsay)
+//------------------------------------------------------------------------------
+
+// Bubble wraps composed art in a cowsay-style speech or thought bubble,
+// with a mascot below pointing up at it. The zero value adds nothing.
+type Bubble struct {
+	Style  string // "say" or "think"; "" for no bubble
+	Mascot string // one of MascotNames, or "" for the bubble alone
+}
+
+// mascots are drawn below the bubble; "$t" marks where the trail of
+// thought runs up to it.
+var mascots = map[string]string{
+	"cow": `        $t   ^__^
+         $t  (oo)\_______
+            (__)\       )\/\
+                ||----w |
+                ||     ||`,
+	"tux": `   $t
+    $t
+        .--.
+       |o_o |
+       |:_/ |
+      //   \ \
+     (|     | )
+    /'\_   _/` + "`" + `\
+    \___)=(___/`,
+	"cat": `  $t
+   $t   /\_/\
+       ( o.o )
+        > ^ <`,
+}
+
+// MascotNames lists the mascots in name order.
+func MascotNames() []string {
+	names := make([]string, 0, len(mascots))
+	for name := range mascots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithBubble wraps the art in b.
+func WithBubble(b Bubble) Option { return func(o *Options) { o.Bubble = b } }
+
+// bubble returns a wrapped in b.
+func (a Art) bubble(b Bubble) (Art, error) {
+	var trail string
+	switch b.Style {
+	case "":
+		return a, nil
+	case "say":
+		trail = `\`
+	case "think":
+		trail = "o"
+	default:
+		return Art{}, fmt.Errorf("unknown bubble %q (want say or think)", b.Style)
+	}
+	mascot, ok := mascots[b.Mascot]
+	if !ok && b.Mascot != "" {
+		return Art{}, fmt.Errorf("unknown mascot %q (want %s)", b.Mascot, strings.Join(MascotNames(), ", "))
+	}
+
+	lines := []string{" " + strings.Repeat("_", a.Width+2)}
+	for y, row := range a.Rows {
+		left, right := "|", "|"
+		switch {
+		case b.Style == "think":
+			left, right = "(", ")"
+		case a.height() == 1:
+			left, right = "<", ">"
+		case y == 0:
+			left, right = "/", `\`
+		case y == a.height()-1:
+			left, right = `\`, "/"
+		}
+		lines = append(lines, left+" "+string(row)+" "+right)
+	}
+	lines = append(lines, " "+strings.Repeat("-", a.Width+2))
+	if mascot != "" {
+		lines = append(lines, strings.Split(strings.ReplaceAll(mascot, "$t", trail), "\n")...)
+	}
+	return newArt(lines), nil
+}
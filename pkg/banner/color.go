@@ -0,0 +1,116 @@
+package banner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Colors
+//------------------------------------------------------------------------------
+
+// Color is a 24-bit RGB color.
+type Color struct{ R, G, B int }
+
+func (c Color) Hex() string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+
+// Lerp blends a toward b by t in [0,1].
+func Lerp(a, b Color, t float64) Color {
+	return Color{
+		R: int(float64(a.R) + (float64(b.R)-float64(a.R))*t),
+		G: int(float64(a.G) + (float64(b.G)-float64(a.G))*t),
+		B: int(float64(a.B) + (float64(b.B)-float64(a.B))*t),
+	}
+}
+
+// ParseHex accepts "#rrggbb" or "#rgb".
+func ParseHex(s string) (Color, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "#") {
+		return Color{}, false
+	}
+	s = s[1:]
+	var r, g, b int
+	switch len(s) {
+	case 6:
+		_, err := fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
+		return Color{r, g, b}, err == nil
+	case 3:
+		var r1, g1, b1 byte
+		_, err := fmt.Sscanf(s, "%1x%1x%1x", &r1, &g1, &b1)
+		return Color{int(r1) * 17, int(g1) * 17, int(b1) * 17}, err == nil
+	default:
+		return Color{}, false
+	}
+}
+
+// HSV helpers for hue rotation
+
+// ToHSV returns hue in degrees and saturation/value in [0,1].
+func (c Color) ToHSV() (h, s, v float64) {
+	r := float64(c.R) / 255.0
+	g := float64(c.G) / 255.0
+	b := float64(c.B) / 255.0
+	maxv := math.Max(r, math.Max(g, b))
+	minv := math.Min(r, math.Min(g, b))
+	d := maxv - minv
+	v = maxv
+	if maxv == 0 { // black
+		return 0, 0, 0
+	}
+	s = 0
+	if maxv != 0 {
+		s = d / maxv
+	}
+	if d == 0 {
+		h = 0
+	} else {
+		switch maxv {
+		case r:
+			h = (g - b) / d
+			if g < b {
+				h += 6
+			}
+		case g:
+			h = (b-r)/d + 2
+		case b:
+			h = (r-g)/d + 4
+		}
+		h *= 60
+	}
+	return
+}
+
+// HSV builds a color from hue in degrees and saturation/value in [0,1].
+func HSV(h, s, v float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60.0, 2)-1))
+	m := v - c
+	var r1, g1, b1 float64
+	switch {
+	case h < 60:
+		r1, g1, b1 = c, x, 0
+	case h < 120:
+		r1, g1, b1 = x, c, 0
+	case h < 180:
+		r1, g1, b1 = 0, c, x
+	case h < 240:
+		r1, g1, b1 = 0, x, c
+	case h < 300:
+		r1, g1, b1 = x, 0, c
+	default:
+		r1, g1, b1 = c, 0, x
+	}
+	return Color{int((r1 + m) * 255), int((g1 + m) * 255), int((b1 + m) * 255)}
+}
+
+// RotateHue shifts c around the color wheel by delta degrees.
+func RotateHue(c Color, delta float64) Color {
+	h, s, v := c.ToHSV()
+	return HSV(h+delta, s, v)
+}
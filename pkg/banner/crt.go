@@ -0,0 +1,82 @@
+package banner
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+//------------------------------------------------------------------------------
+// CRT scanlines
+//------------------------------------------------------------------------------
+
+// CRT makes the banner look shown on an old tube: alternate rows dimmed as
+// scanlines, rows now and then jittering a cell sideways, and with Curve,
+// edges darkened and the corners rounded off as on a curved screen. It is
+// meant to run last, over every other effect. The frame grows by a column
+// each side for the jitter.
+type CRT struct {
+	Dim    float64 // how much darker the scanlines are, 0 to 1
+	Jitter bool
+	Curve  bool
+
+	rng   *rand.Rand
+	shift map[int]int // rows out of place this tick
+}
+
+func NewCRT(dim float64, jitter, curve bool) *CRT {
+	return &CRT{Dim: dim, Jitter: jitter, Curve: curve}
+}
+
+func (c *CRT) Init(_, _ int) {
+	c.rng = rand.New(rand.NewPCG(3, 4))
+	c.shift = map[int]int{}
+}
+
+func (c *CRT) Tick() {
+	if c.rng == nil {
+		c.Init(0, 0)
+	}
+	clear(c.shift)
+	if !c.Jitter {
+		return
+	}
+	for y := range 64 { // rows beyond the banner just go unused
+		if c.rng.Float64() < 0.04 {
+			c.shift[y] = 1 - 2*c.rng.IntN(2)
+		}
+	}
+}
+
+func (c *CRT) Apply(f Frame) Frame {
+	w, h := f.Width+2, len(f.Cells)
+	for y, src := range f.Cells {
+		row := make([]Cell, w)
+		for x := range row {
+			row[x] = Cell{Rune: ' ', Blank: true}
+		}
+		copy(row[1+c.shift[y]:], src)
+		for x := range row {
+			if row[x].Blank {
+				continue
+			}
+			light := 1.0
+			if y%2 == 1 {
+				light -= c.Dim
+			}
+			if c.Curve {
+				// Distance from the middle, 0 there and 1 at the edges.
+				dx := math.Abs(2*float64(x)/float64(max(1, w-1)) - 1)
+				dy := math.Abs(2*float64(y)/float64(max(1, h-1)) - 1)
+				if dx*dx*dx*dx+dy*dy*dy*dy > 1.6 { // the rounded-off corners
+					row[x] = Cell{Rune: ' ', Blank: true}
+					continue
+				}
+				light *= 1 - 0.45*(dx*dx*dx*dx+dy*dy*dy*dy)/2
+			}
+			row[x].FG = Lerp(Color{}, row[x].FG, max(0, light))
+		}
+		f.Cells[y] = row
+	}
+	f.Width = w
+	return f
+}
@@ -0,0 +1,61 @@
+package banner
+
+//------------------------------------------------------------------------------
+// Palette cycling
+//------------------------------------------------------------------------------
+
+// PaletteCycle is demoscene color cycling: the gradient is cut into Bands
+// flat stripes across the banner, and every Every ticks each stripe takes
+// the next one's color, so the bands march across instead of the hues
+// turning smoothly. With few colors it suits 256-color terminals.
+type PaletteCycle struct {
+	Bands int // stripes, 2 or more
+	Every int // ticks between steps, 1 or more
+	t     int
+}
+
+func NewPaletteCycle(bands, every int) *PaletteCycle {
+	return &PaletteCycle{Bands: max(2, bands), Every: max(1, every)}
+}
+
+func (p *PaletteCycle) Init(_, _ int) { p.t = 0 }
+
+func (p *PaletteCycle) Tick() { p.t++ }
+
+func (p *PaletteCycle) Apply(f Frame) Frame {
+	n := max(2, p.Bands)
+	if f.Width < n {
+		n = max(1, f.Width)
+	}
+	band := func(x int) int { return x * n / max(1, f.Width) }
+
+	// Each band's color is the mean of the ink in it.
+	sums := make([][4]int, n)
+	for _, row := range f.Cells {
+		for x, c := range row {
+			if !c.Blank && x < f.Width {
+				s := &sums[band(x)]
+				s[0], s[1], s[2], s[3] = s[0]+c.FG.R, s[1]+c.FG.G, s[2]+c.FG.B, s[3]+1
+			}
+		}
+	}
+	palette := make([]Color, n)
+	for i, s := range sums {
+		switch {
+		case s[3] > 0:
+			palette[i] = Color{s[0] / s[3], s[1] / s[3], s[2] / s[3]}
+		case i > 0:
+			palette[i] = palette[i-1] // an empty band borrows its neighbour's
+		}
+	}
+
+	step := p.t / max(1, p.Every)
+	for _, row := range f.Cells {
+		for x, c := range row {
+			if !c.Blank && x < f.Width {
+				row[x].FG = palette[(band(x)+step)%n]
+			}
+		}
+	}
+	return f
+}
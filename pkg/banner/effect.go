@@ -0,0 +1,102 @@
+package banner
+
+import (
+	"math"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Effects
+//------------------------------------------------------------------------------
+
+// Effect transforms colored frames over time. Owners call Init when the
+// effect joins a stack or the art changes size, Tick once per animation
+// step, and Apply on every colored frame. Apply may modify f in place.
+type Effect interface {
+	Init(width, height int)
+	Tick()
+	Apply(f Frame) Frame
+}
+
+// Timed is implemented by effects that keep time rather than count ticks.
+// Owners call SetTickDuration when the effect joins a stack and whenever
+// the tick rate changes.
+type Timed interface {
+	SetTickDuration(d time.Duration)
+}
+
+// ApplyEffects runs f through effects in order.
+func ApplyEffects(f Frame, effects []Effect) Frame {
+	for _, e := range effects {
+		f = e.Apply(f)
+	}
+	return f
+}
+
+// HueCycle rotates every color around the wheel, Step degrees per tick,
+// except the fixed colors of marked words.
+type HueCycle struct {
+	Step  float64 // degrees per tick
+	Shift float64 // current rotation in degrees
+}
+
+func NewHueCycle(step float64) *HueCycle { return &HueCycle{Step: step} }
+
+func (h *HueCycle) Init(_, _ int) {}
+
+func (h *HueCycle) Tick() { h.Shift = math.Mod(h.Shift+h.Step, 360) }
+
+func (h *HueCycle) Apply(f Frame) Frame {
+	if h.Shift == 0 {
+		return f
+	}
+	// Columns share colors down the rows; rotate each distinct color once.
+	rotated := map[Color]Color{}
+	for _, row := range f.Cells {
+		for x := range row {
+			if row[x].Blank || row[x].Fixed {
+				continue
+			}
+			c, ok := rotated[row[x].FG]
+			if !ok {
+				c = RotateHue(row[x].FG, h.Shift)
+				rotated[row[x].FG] = c
+			}
+			row[x].FG = c
+		}
+	}
+	return f
+}
+
+// Wave slides rows sideways along a sine wave that travels down the banner.
+// The frame widens by 2×Amplitude so nothing is clipped.
+type Wave struct {
+	Amplitude int     // columns
+	Length    float64 // rows per full wave
+	Speed     float64 // radians per tick
+	phase     float64
+}
+
+func NewWave(amplitude int) *Wave { return &Wave{Amplitude: amplitude, Length: 8, Speed: 0.4} }
+
+func (w *Wave) Init(_, _ int) { w.phase = 0 }
+
+func (w *Wave) Tick() { w.phase += w.Speed }
+
+func (w *Wave) Apply(f Frame) Frame {
+	if w.Amplitude <= 0 || w.Length == 0 {
+		return f
+	}
+	width := f.Width + 2*w.Amplitude
+	for y, row := range f.Cells {
+		off := w.Amplitude + int(math.Round(float64(w.Amplitude)*math.Sin(w.phase+2*math.Pi*float64(y)/w.Length)))
+		out := make([]Cell, width)
+		for x := range out {
+			out[x] = Cell{Rune: ' ', Blank: true}
+		}
+		copy(out[off:], row)
+		f.Cells[y] = out
+	}
+	f.Width = width
+	return f
+}
@@ -0,0 +1,67 @@
+package banner
+
+import (
+	"fmt"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Flashing on a beat
+//------------------------------------------------------------------------------
+
+// MinFlashPeriod is the shortest beat a Flash keeps, whatever it is asked
+// for: 2.5 flashes a second, under the three a second that guidelines on
+// photosensitive seizures (WCAG 2.3.1) set as the limit.
+const MinFlashPeriod = 400 * time.Millisecond
+
+// Flash inverts or brightens the whole banner for the first half of every
+// beat. It counts time by its ticks, so it keeps the beat in exports too;
+// owners say how long a tick is through SetTickDuration.
+type Flash struct {
+	Every  time.Duration // the beat; raised to MinFlashPeriod if shorter
+	Invert bool          // invert the colors rather than brighten them
+	Still  bool          // never flash, for reduced motion
+
+	tick    time.Duration
+	elapsed time.Duration
+}
+
+// NewFlash flashes every beat, inverting the colors or else brightening
+// them.
+func NewFlash(every time.Duration, invert bool) (*Flash, error) {
+	if every < MinFlashPeriod {
+		return nil, fmt.Errorf("every %v is too fast: at least %v, to be safe for photosensitive viewers", every, MinFlashPeriod)
+	}
+	return &Flash{Every: every, Invert: invert, tick: 60 * time.Millisecond}, nil
+}
+
+func (f *Flash) SetTickDuration(d time.Duration) { f.tick = d }
+
+func (f *Flash) Init(_, _ int) { f.elapsed = 0 }
+
+func (f *Flash) Tick() { f.elapsed += f.tick }
+
+// lit reports whether this tick is in the flash.
+func (f *Flash) lit() bool {
+	every := max(f.Every, MinFlashPeriod)
+	return !f.Still && f.elapsed%every < every/2
+}
+
+func (f *Flash) Apply(fr Frame) Frame {
+	if !f.lit() {
+		return fr
+	}
+	for _, row := range fr.Cells {
+		for x, c := range row {
+			if c.Blank {
+				continue
+			}
+			if f.Invert {
+				row[x].FG = Color{255 - c.FG.R, 255 - c.FG.G, 255 - c.FG.B}
+			} else {
+				row[x].FG = Lerp(c.FG, Color{255, 255, 255}, 0.7)
+			}
+		}
+	}
+	return fr
+}
@@ -0,0 +1,192 @@
+package banner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	figure "github.com/common-nighthawk/go-figure"
+)
+
+//------------------------------------------------------------------------------
+// Fonts
+//------------------------------------------------------------------------------
+
+// builtinFonts lists the go-figure fonts offered for cycling.
+var builtinFonts = []string{
+	"standard", "big", "doom", "slant", "shadow", "block", "banner", "larry3d", "speed", "smslant", "small", "isometric1",
+	"3-d", "3x5", "5lineoblique", "acrobatic", "alligator", "alligator2", "alphabet",
+	"avatar", "banner3-D", "banner3", "banner4", "barbwire", "basic", "bell", "bigchief",
+	"binary", "bubble", "bulbhead", "calgphy2", "caligraphy", "catwalk", "chunky",
+	"coinstak", "colossal", "computer", "contessa", "contrast", "cosmic", "cosmike",
+	"cricket", "cursive", "cyberlarge", "cybermedium", "cybersmall", "diamond", "digital", "doh", "dotmatrix", "drpepper",
+	"eftichess", "eftifont", "eftipiti", "eftirobot", "eftitalic", "eftiwall", "eftiwater",
+	"epic", "fender", "fourtops", "fuzzy", "goofy", "gothic", "graffiti", "hollywood",
+	"invita", "isometric2", "isometric3", "isometric4", "italic", "ivrit", "jazmine",
+	"jerusalem", "katakana", "kban", "lcd", "lean", "letters", "linux", "lockergnome",
+	"madrid", "marquee", "maxfour", "mike", "mini", "mirror", "mnemonic", "morse",
+	"moscow", "nancyj-fancy", "nancyj-underlined", "nancyj", "nipples", "ntgreek", "o8",
+	"ogre", "pawp", "peaks", "pebbles", "pepper", "poison", "puffy", "pyramid", "rectangles",
+	"relief", "relief2", "rev", "roman", "rot13", "rounded", "rowancap", "rozzo", "runic",
+	"runyc", "sblood", "script", "serifcap", "short", "slide", "slscript", "smisome1", "smkeyboard",
+	"smscript", "smshadow", "smtengwar", "stampatello", "starwars", "stellar", "stop",
+	"straight", "tanja", "tengwar", "term", "thick", "thin", "threepoint", "ticks", "ticksslant",
+	"tinker-toy", "tombstone", "trek", "tsalagi", "twopoint", "univers", "usaflag", "wavy",
+	"weird",
+}
+
+var (
+	customMu    sync.RWMutex
+	customFonts = map[string]string{} // name -> .flf path
+)
+
+var (
+	shippedOnce  sync.Once
+	shippedFonts []string // builtinFonts present in this go-figure build
+)
+
+// shipped checks builtinFonts, once, against the fonts go-figure embeds, so
+// cycling never lands on one it would panic on.
+func shipped() []string {
+	shippedOnce.Do(func() {
+		have := map[string]bool{}
+		for _, name := range figure.AssetNames() {
+			have[name] = true
+		}
+		for _, f := range builtinFonts {
+			if have["fonts/"+f+".flf"] {
+				shippedFonts = append(shippedFonts, f)
+			}
+		}
+	})
+	return shippedFonts
+}
+
+// BuiltinFonts returns the built-in font names in cycling order.
+func BuiltinFonts() []string { return append([]string(nil), shipped()...) }
+
+// IsBuiltinFont reports whether name ships with go-figure.
+func IsBuiltinFont(name string) bool {
+	for _, f := range shipped() {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterFont makes a FIGlet .flf file available under name. Built-in
+// and segment font names cannot be replaced; RegisterFont reports whether name was added.
+func RegisterFont(name, path string) bool {
+	if _, ok := segmentThickness(name); ok || IsBuiltinFont(name) {
+		return false
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	customFonts[name] = path
+	return true
+}
+
+// FontPath returns the file behind a registered custom font.
+func FontPath(name string) (string, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	p, ok := customFonts[name]
+	return p, ok
+}
+
+// FontNames lists built-in fonts, then the SegmentFonts, then custom fonts
+// in name order.
+func FontNames() []string {
+	names := append(BuiltinFonts(), SegmentFonts...)
+	customMu.RLock()
+	custom := make([]string, 0, len(customFonts))
+	for name := range customFonts {
+		custom = append(custom, name)
+	}
+	customMu.RUnlock()
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+var glyphSizes = struct {
+	sync.Mutex
+	m map[string][2]int
+}{m: map[string][2]int{}}
+
+// GlyphSize is the size of a wide letter (W) in font, for estimating how
+// big a text's art will be before composing it.
+func GlyphSize(font string) (width, height int, err error) {
+	glyphSizes.Lock()
+	size, ok := glyphSizes.m[font]
+	glyphSizes.Unlock()
+	if !ok {
+		lines, err := figureLines("W", font)
+		if err != nil {
+			return 0, 0, err
+		}
+		a := newArt(lines)
+		size = [2]int{a.Width, a.height()}
+		glyphSizes.Lock()
+		glyphSizes.m[font] = size
+		glyphSizes.Unlock()
+	}
+	return size[0], size[1], nil
+}
+
+// CheckFont reports why the .flf file at path can't be used as a font, or
+// nil if it can.
+func CheckFont(path string) error {
+	_, err := figureFile("Aa", path)
+	return err
+}
+
+// figureLines renders txt in the named font, one string per row.
+func figureLines(txt, font string) ([]string, error) {
+	if t, ok := segmentThickness(font); ok {
+		lines, err := segmentLines(txt, t)
+		if err != nil {
+			return nil, fmt.Errorf("font %s: %w", font, err)
+		}
+		return lines, nil
+	}
+	var s string
+	var err error
+	if p, ok := FontPath(font); ok {
+		s, err = figureFile(txt, p)
+	} else {
+		s, err = figureString(txt, func() string { return figure.NewFigure(txt, font, true).String() })
+	}
+	if err != nil {
+		return nil, fmt.Errorf("font %s: %w", font, err)
+	}
+	return strings.Split(strings.TrimRight(s, "\n"), "\n"), nil
+}
+
+// figureFile renders txt in the font file at path.
+func figureFile(txt, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return figureString(txt, func() string { return figure.NewFigureWithFont(txt, f, true).String() })
+}
+
+// figureString runs render, turning go-figure's panics on fonts it can't
+// find or parse into errors.
+func figureString(txt string, render func() string) (s string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s, err = "", fmt.Errorf("%v", r)
+		}
+	}()
+	s = render()
+	if s == "" && txt != "" {
+		return "", errors.New("not a FIGlet font")
+	}
+	return s, nil
+}
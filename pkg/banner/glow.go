@@ -0,0 +1,87 @@
+package banner
+
+import (
+	"fmt"
+	"math"
+)
+
+//------------------------------------------------------------------------------
+// Neon glow
+//------------------------------------------------------------------------------
+
+// Glow haloes the letters like a neon sign: blank cells within Radius of
+// ink get a shade block in the nearby letters' colors, dimmer further out.
+// It reads best over block fills. The frame grows by Radius all round so
+// the halo isn't cut off.
+type Glow struct {
+	Radius int // cells, 1 or 2
+}
+
+// NewGlow makes a glow radius cells wide.
+func NewGlow(radius int) (*Glow, error) {
+	if radius < 1 || radius > 2 {
+		return nil, fmt.Errorf("radius %d out of range (1-2)", radius)
+	}
+	return &Glow{Radius: radius}, nil
+}
+
+func (g *Glow) Init(_, _ int) {}
+
+func (g *Glow) Tick() {}
+
+// glowShades are the halo by distance in cells: 1, then 2.
+var glowShades = []struct {
+	r   rune
+	dim float64
+}{{'▒', 0.5}, {'░', 0.75}}
+
+func (g *Glow) Apply(f Frame) Frame {
+	r := g.Radius
+	if r <= 0 {
+		return f
+	}
+	src := func(x, y int) (Cell, bool) {
+		x, y = x-r, y-r
+		if y < 0 || y >= len(f.Cells) || x < 0 || x >= len(f.Cells[y]) {
+			return Cell{}, false
+		}
+		c := f.Cells[y][x]
+		return c, !c.Blank && c.Rune != ' '
+	}
+	w, h := f.Width+2*r, len(f.Cells)+2*r
+	cells := make([][]Cell, h)
+	for y := range cells {
+		row := make([]Cell, w)
+		for x := range row {
+			if c, ink := src(x, y); ink {
+				row[x] = c
+				continue
+			}
+			// Blend the ink around, nearer counting more.
+			var sr, sg, sb, weight float64
+			nearest := math.Inf(1)
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					c, ink := src(x+dx, y+dy)
+					d := math.Hypot(float64(dx), 2*float64(dy)) / 2 // rows are twice as tall
+					if !ink || d > float64(r) {
+						continue
+					}
+					wt := 1 / (d * d)
+					sr, sg, sb, weight = sr+wt*float64(c.FG.R), sg+wt*float64(c.FG.G), sb+wt*float64(c.FG.B), weight+wt
+					nearest = min(nearest, d)
+				}
+			}
+			if weight == 0 {
+				row[x] = Cell{Rune: ' ', Blank: true}
+				continue
+			}
+			shade := glowShades[min(len(glowShades)-1, max(0, int(math.Ceil(nearest))-1))]
+			c := Color{int(sr / weight), int(sg / weight), int(sb / weight)}
+			row[x] = Cell{Rune: shade.r, FG: Lerp(c, Color{}, shade.dim)}
+		}
+		cells[y] = row
+	}
+	f.Cells, f.Width, f.Height = cells, w, h
+	return f
+}
@@ -0,0 +1,38 @@
+package banner
+
+import "math/rand/v2"
+
+//------------------------------------------------------------------------------
+// Film grain
+//------------------------------------------------------------------------------
+
+// Grain jitters the brightness of every cell by up to Amount, fresh each
+// tick, for a film-grain texture on flat block fills. The same seed gives
+// the same grain, so exports are repeatable.
+type Grain struct {
+	Amount float64 // 0 to 1, as a fraction of full brightness
+	seed   uint64
+	t      uint64
+}
+
+func NewGrain(amount float64) *Grain { return &Grain{Amount: amount, seed: 5} }
+
+func (g *Grain) Init(_, _ int) { g.t = 0 }
+
+func (g *Grain) Tick() { g.t++ }
+
+func (g *Grain) Apply(f Frame) Frame {
+	rng := rand.New(rand.NewPCG(g.seed, g.t))
+	for _, row := range f.Cells {
+		for x, c := range row {
+			if c.Blank {
+				continue
+			}
+			d := (2*rng.Float64() - 1) * g.Amount * 255
+			row[x].FG = Color{clampByte(float64(c.FG.R) + d), clampByte(float64(c.FG.G) + d), clampByte(float64(c.FG.B) + d)}
+		}
+	}
+	return f
+}
+
+func clampByte(v float64) int { return int(min(255, max(0, v))) }
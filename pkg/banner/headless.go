@@ -0,0 +1,39 @@
+package banner
+
+import (
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+//------------------------------------------------------------------------------
+// Headless rendering
+//------------------------------------------------------------------------------
+
+// headless renders 24-bit color regardless of where output goes, so the
+// same inputs always produce the same bytes.
+var headless = func() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	return r
+}()
+
+// ANSI renders the frame with 24-bit color escapes, independent of the
+// terminal.
+func (f Frame) ANSI() string { return f.Styled(headless) }
+
+// Headless renders text deterministically: truecolor escapes, the hue shift
+// given by WithHueShift rather than a clock, and, when width and height are
+// positive, the banner centered on a fixed-size canvas the way the TUI
+// places it.
+func Headless(text string, width, height int, opts ...Option) (string, error) {
+	f, err := Render(text, opts...)
+	if err != nil {
+		return "", err
+	}
+	if width > 0 && height > 0 {
+		f = f.Place(width, height)
+	}
+	return f.ANSI(), nil
+}
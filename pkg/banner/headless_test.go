@@ -0,0 +1,72 @@
+package banner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"glamdm/pkg/banner/bannertest"
+)
+
+func TestHeadlessGolden(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		opts          []Option
+	}{
+		{"headless", 0, 0, []Option{WithFont("standard")}},
+		{"headless_placed", 32, 10, []Option{WithFont("standard")}},
+		{"headless_shifted", 0, 0, []Option{WithFont("standard"), WithHueShift(90)}},
+		{"headless_block", 0, 0, []Option{WithFont("slant"), WithMode(ModeBlock)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Headless("Hi!", tt.width, tt.height, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bannertest.Golden(t, tt.name, out)
+		})
+	}
+}
+
+// TestEncodersGolden renders one frame in every text output format.
+func TestEncodersGolden(t *testing.T) {
+	f, err := Render("Hi", WithFont("standard"), WithStops(Stop{At: 0.5, Color: Color{R: 255}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sauce := &Sauce{Title: "Hi", Author: "me", Group: "us", Date: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name   string
+		encode func() (string, error)
+	}{
+		{"ansi", func() (string, error) { return f.ANSI(), nil }},
+		{"json", func() (string, error) {
+			b, err := json.Marshal(f)
+			return string(b), err
+		}},
+		{"svg", func() (string, error) { return f.SVG(), nil }},
+		{"html", func() (string, error) { return f.HTML(), nil }},
+		{"ans", func() (string, error) { return string(f.ANS(sauce)), nil }},
+		{"irc", func() (string, error) { return f.IRC(false), nil }},
+		{"irc_extended", func() (string, error) { return f.IRC(true), nil }},
+		{"markdown", func() (string, error) { return strings.Join(f.Markdown(false, 0), "\n"), nil }},
+		{"markdown_ansi", func() (string, error) { return strings.Join(f.Markdown(true, 0), "\n"), nil }},
+		{"bash_prompt", func() (string, error) { return f.BashPrompt(), nil }},
+		{"zsh_prompt", func() (string, error) { return f.ZshPrompt(), nil }},
+		{"bash", func() (string, error) { return f.Bash(), nil }},
+		{"powershell", func() (string, error) { return f.PowerShell(), nil }},
+		{"python", func() (string, error) { return f.Python(), nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			bannertest.Golden(t, "encode_"+tt.name, out)
+		})
+	}
+}
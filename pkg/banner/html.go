@@ -0,0 +1,89 @@
+package banner
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// HTML and SVG frames
+//------------------------------------------------------------------------------
+
+// run is a stretch of cells in one row sharing a color; blank runs have no
+// color.
+type run struct {
+	text  string
+	fg    Color
+	blank bool
+}
+
+// runs groups a row into same-colored stretches so markup gets one element
+// per run instead of one per cell.
+func runs(cells []Cell) []run {
+	var out []run
+	var b strings.Builder
+	for i, c := range cells {
+		b.WriteRune(c.Rune)
+		last := i == len(cells)-1
+		if !last {
+			n := cells[i+1]
+			if n.Blank == c.Blank && (c.Blank || n.FG == c.FG) {
+				continue
+			}
+		}
+		out = append(out, run{text: b.String(), fg: c.FG, blank: c.Blank})
+		b.Reset()
+	}
+	return out
+}
+
+// HTML renders the frame as a <pre> block with one colored <span> per run.
+// It is a fragment; callers supply the page and background.
+func (f Frame) HTML() string {
+	var b strings.Builder
+	b.WriteString(`<pre class="banner">`)
+	for y, cells := range f.Cells {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for _, r := range runs(cells) {
+			if r.blank {
+				b.WriteString(r.text)
+				continue
+			}
+			fmt.Fprintf(&b, `<span style="color:%s">%s</span>`, r.fg.Hex(), html.EscapeString(r.text))
+		}
+	}
+	b.WriteString("</pre>")
+	return b.String()
+}
+
+// SVG cell metrics for a 14px monospace font.
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4 // 0.6em, the usual monospace advance
+	svgLineHeight = 17
+)
+
+// SVG renders the frame as a standalone SVG document with a transparent
+// background, one <text> per row and one <tspan> per colored run.
+func (f Frame) SVG() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%d" font-family="monospace" font-size="%d" xml:space="preserve">`,
+		float64(f.Width)*svgCellWidth, f.Height*svgLineHeight, svgFontSize)
+	for y, cells := range f.Cells {
+		fmt.Fprintf(&b, `<text x="0" y="%d">`, (y+1)*svgLineHeight-4)
+		x := 0
+		for _, r := range runs(cells) {
+			n := len([]rune(r.text))
+			if !r.blank {
+				fmt.Fprintf(&b, `<tspan x="%g" fill="%s">%s</tspan>`, float64(x)*svgCellWidth, r.fg.Hex(), html.EscapeString(r.text))
+			}
+			x += n
+		}
+		b.WriteString("</text>")
+	}
+	b.WriteString("</svg>")
+	return b.String()
+}
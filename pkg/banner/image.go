@@ -0,0 +1,146 @@
+package banner
+
+import (
+	"image"
+	_ "image/gif" // decoders for LoadImage
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+//------------------------------------------------------------------------------
+// Images as ASCII art
+//------------------------------------------------------------------------------
+
+// imageRamp runs from no ink to the most, for light art on a dark terminal.
+const imageRamp = " .:-=+*#%@"
+
+// LoadImage decodes a PNG, JPEG or GIF file.
+func LoadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// ImageFit is the largest width, in cells, at which img fits in width by
+// height cells. Cells are taken to be twice as tall as wide.
+func ImageFit(img image.Image, width, height int) int {
+	b := img.Bounds()
+	if b.Dy() == 0 {
+		return max(1, width)
+	}
+	return max(1, min(width, b.Dx(), height*2*b.Dx()/b.Dy()))
+}
+
+// ImageArt draws img width cells wide in characters of imageRamp, denser
+// where the image is brighter. Each cell averages the pixels under it;
+// transparent pixels count as black, so they stay blank. The result is
+// ordinary Art, colored by gradients and render modes like a banner.
+func ImageArt(img image.Image, width int) Art {
+	b := img.Bounds()
+	if b.Empty() {
+		return Art{}
+	}
+	width = max(1, min(width, b.Dx()))
+	height := max(1, b.Dy()*width/b.Dx()/2)
+
+	a := Art{Rows: make([][]rune, height), Width: width}
+	ramp := []rune(imageRamp)
+	for y := range height {
+		y0, y1 := b.Min.Y+y*b.Dy()/height, b.Min.Y+(y+1)*b.Dy()/height
+		row := make([]rune, width)
+		for x := range width {
+			x0, x1 := b.Min.X+x*b.Dx()/width, b.Min.X+(x+1)*b.Dx()/width
+			l := luminance(img, image.Rect(x0, y0, max(x1, x0+1), max(y1, y0+1)))
+			row[x] = ramp[int(l*float64(len(ramp)-1)+0.5)]
+		}
+		a.Rows[y] = row
+	}
+	return a
+}
+
+// luminance is the mean brightness of img over r, from 0 to 1, with
+// transparent pixels blended onto black.
+func luminance(img image.Image, r image.Rectangle) float64 {
+	var sum float64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA() // premultiplied
+			sum += 0.299*float64(cr) + 0.587*float64(cg) + 0.114*float64(cb)
+		}
+	}
+	return sum / float64(r.Dx()*r.Dy()) / 0xffff
+}
+
+//------------------------------------------------------------------------------
+// Image backdrops
+//------------------------------------------------------------------------------
+
+// ImageFrame draws img in its own colors on a width by height canvas,
+// cropped around the middle to fill it: characters of imageRamp by
+// brightness, or solid blocks if solid.
+func ImageFrame(img image.Image, width, height int, solid bool) Frame {
+	b := img.Bounds()
+	if b.Empty() || width <= 0 || height <= 0 {
+		return Frame{}
+	}
+	// Cells are twice as tall as wide, so the canvas is width by 2*height
+	// in square pixels.
+	crop := b
+	if b.Dx()*2*height > b.Dy()*width {
+		w := b.Dy() * width / (2 * height)
+		crop.Min.X += (b.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + max(1, w)
+	} else {
+		h := b.Dx() * 2 * height / width
+		crop.Min.Y += (b.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + max(1, h)
+	}
+
+	f := Frame{Cells: make([][]Cell, height), Width: width, Height: height}
+	ramp := []rune(imageRamp)
+	for y := range height {
+		y0, y1 := crop.Min.Y+y*crop.Dy()/height, crop.Min.Y+(y+1)*crop.Dy()/height
+		row := make([]Cell, width)
+		for x := range width {
+			x0, x1 := crop.Min.X+x*crop.Dx()/width, crop.Min.X+(x+1)*crop.Dx()/width
+			p := average(img, image.Rect(x0, y0, max(x1, x0+1), max(y1, y0+1)))
+			r := ' '
+			if solid && p.Opaque {
+				r = '█'
+			} else if p.Opaque {
+				l := (0.299*float64(p.R) + 0.587*float64(p.G) + 0.114*float64(p.B)) / 255
+				r = ramp[int(l*float64(len(ramp)-1)+0.5)]
+			}
+			row[x] = Cell{Rune: r, FG: p.Color, Blank: r == ' '}
+		}
+		f.Cells[y] = row
+	}
+	return f
+}
+
+// Over centers f on bg, its blank cells showing bg through. bg's colors
+// are darkened by dim, from 0 (as they are) to 1 (black), so the banner
+// stands out.
+func (f Frame) Over(bg Frame, dim float64) Frame {
+	dim = min(1, max(0, dim))
+	out := bg.Place(max(bg.Width, f.Width), max(bg.Height, f.Height))
+	for _, row := range out.Cells {
+		for x, c := range row {
+			row[x].FG = Color{int(float64(c.FG.R) * (1 - dim)), int(float64(c.FG.G) * (1 - dim)), int(float64(c.FG.B) * (1 - dim))}
+		}
+	}
+	left, top := (out.Width-f.Width)/2, (out.Height-f.Height)/2
+	for y, row := range f.Cells {
+		for x, c := range row {
+			if !c.Blank {
+				out.Cells[top+y][left+x] = c
+			}
+		}
+	}
+	return out
+}
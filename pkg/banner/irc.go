@@ -0,0 +1,86 @@
+package banner
+
+import (
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// IRC color codes
+//------------------------------------------------------------------------------
+
+// ircPalette is mIRC's color table: codes 0-15 are the classic colors every
+// client shows, 16-98 the extended ones most modern clients also do.
+var ircPalette = [99]Color{
+	{255, 255, 255}, {0, 0, 0}, {0, 0, 127}, {0, 147, 0}, {255, 0, 0}, {127, 0, 0}, {156, 0, 156}, {252, 127, 0},
+	{255, 255, 0}, {0, 252, 0}, {0, 147, 147}, {0, 255, 255}, {0, 0, 252}, {255, 0, 255}, {127, 127, 127}, {210, 210, 210},
+
+	{0x47, 0x00, 0x00}, {0x47, 0x21, 0x00}, {0x47, 0x47, 0x00}, {0x32, 0x47, 0x00}, {0x00, 0x47, 0x00}, {0x00, 0x47, 0x2c},
+	{0x00, 0x47, 0x47}, {0x00, 0x27, 0x47}, {0x00, 0x00, 0x47}, {0x2e, 0x00, 0x47}, {0x47, 0x00, 0x47}, {0x47, 0x00, 0x2a},
+	{0x74, 0x00, 0x00}, {0x74, 0x3a, 0x00}, {0x74, 0x74, 0x00}, {0x51, 0x74, 0x00}, {0x00, 0x74, 0x00}, {0x00, 0x74, 0x49},
+	{0x00, 0x74, 0x74}, {0x00, 0x40, 0x74}, {0x00, 0x00, 0x74}, {0x4b, 0x00, 0x74}, {0x74, 0x00, 0x74}, {0x74, 0x00, 0x45},
+	{0xb5, 0x00, 0x00}, {0xb5, 0x63, 0x00}, {0xb5, 0xb5, 0x00}, {0x7d, 0xb5, 0x00}, {0x00, 0xb5, 0x00}, {0x00, 0xb5, 0x71},
+	{0x00, 0xb5, 0xb5}, {0x00, 0x63, 0xb5}, {0x00, 0x00, 0xb5}, {0x75, 0x00, 0xb5}, {0xb5, 0x00, 0xb5}, {0xb5, 0x00, 0x6b},
+	{0xff, 0x00, 0x00}, {0xff, 0x8c, 0x00}, {0xff, 0xff, 0x00}, {0xb2, 0xff, 0x00}, {0x00, 0xff, 0x00}, {0x00, 0xff, 0xa0},
+	{0x00, 0xff, 0xff}, {0x00, 0x8c, 0xff}, {0x00, 0x00, 0xff}, {0xa5, 0x00, 0xff}, {0xff, 0x00, 0xff}, {0xff, 0x00, 0x98},
+	{0xff, 0x59, 0x59}, {0xff, 0xb4, 0x59}, {0xff, 0xff, 0x71}, {0xcf, 0xff, 0x60}, {0x6f, 0xff, 0x6f}, {0x65, 0xff, 0xc9},
+	{0x6d, 0xff, 0xff}, {0x59, 0xb4, 0xff}, {0x59, 0x59, 0xff}, {0xc4, 0x59, 0xff}, {0xff, 0x66, 0xff}, {0xff, 0x59, 0xbc},
+	{0xff, 0x9c, 0x9c}, {0xff, 0xd3, 0x9c}, {0xff, 0xff, 0x9c}, {0xe2, 0xff, 0x9c}, {0x9c, 0xff, 0x9c}, {0x9c, 0xff, 0xdb},
+	{0x9c, 0xff, 0xff}, {0x9c, 0xd3, 0xff}, {0x9c, 0x9c, 0xff}, {0xdc, 0x9c, 0xff}, {0xff, 0x9c, 0xff}, {0xff, 0x94, 0xd3},
+	{0x00, 0x00, 0x00}, {0x13, 0x13, 0x13}, {0x28, 0x28, 0x28}, {0x36, 0x36, 0x36}, {0x4d, 0x4d, 0x4d}, {0x65, 0x65, 0x65},
+	{0x81, 0x81, 0x81}, {0x9f, 0x9f, 0x9f}, {0xbc, 0xbc, 0xbc}, {0xe2, 0xe2, 0xe2}, {0xff, 0xff, 0xff},
+}
+
+// IRC encodes the frame with mIRC color codes, one line per row, for
+// pasting into a channel. With extended set it uses all 99 colors;
+// otherwise only the 16 classic ones, for older clients.
+func (f Frame) IRC(extended bool) string {
+	n := 16
+	if extended {
+		n = len(ircPalette)
+	}
+	var b strings.Builder
+	for y, cells := range f.Cells {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		code := -1
+		for _, c := range cells {
+			if !c.Blank {
+				if k := nearestIRC(c.FG, n); k != code {
+					// Always two digits, so a digit glyph can't extend the code.
+					fmt.Fprintf(&b, "\x03%02d", k)
+					if c.Rune == ',' {
+						b.WriteString("\x02\x02") // or ",N" would read as a background
+					}
+					code = k
+				}
+			}
+			b.WriteRune(c.Rune)
+		}
+		if code >= 0 {
+			b.WriteByte('\x0f')
+		}
+	}
+	return b.String()
+}
+
+// nearestIRC is the code among the first n whose color is closest to c.
+func nearestIRC(c Color, n int) int {
+	best, bestD := 0, -1
+	for k, p := range ircPalette[:n] {
+		if d := colorDistance(c, p); bestD < 0 || d < bestD {
+			best, bestD = k, d
+		}
+	}
+	return best
+}
+
+// colorDistance is a cheap perceptual distance: squared RGB differences
+// weighted by how sensitive the eye is to each, more so for red at the red
+// end ("redmean").
+func colorDistance(a, b Color) int {
+	rm := (a.R + b.R) / 2
+	dr, dg, db := a.R-b.R, a.G-b.G, a.B-b.B
+	return (512+rm)*dr*dr>>8 + 4*dg*dg + (767-rm)*db*db>>8
+}
@@ -0,0 +1,23 @@
+package banner
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+//------------------------------------------------------------------------------
+// iTerm2 inline images
+//------------------------------------------------------------------------------
+
+// ITerm2 returns the frame as a PNG in an OSC 1337 inline image, which
+// iTerm2 (and WezTerm) display at the cursor, sized in pixels so the
+// terminal doesn't stretch it to the window.
+func (f Frame) ITerm2(scale int) (string, error) {
+	data, err := f.PNG(scale)
+	if err != nil {
+		return "", err
+	}
+	scale = max(1, scale)
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%dpx;height=%dpx;preserveAspectRatio=1:%s\a",
+		len(data), f.Width*scale*RasterCellW, f.Height*scale*RasterCellH, base64.StdEncoding.EncodeToString(data)), nil
+}
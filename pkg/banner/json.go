@@ -0,0 +1,41 @@
+package banner
+
+import "encoding/json"
+
+//------------------------------------------------------------------------------
+// JSON frames
+//------------------------------------------------------------------------------
+
+// jsonFrame is the wire form of a Frame:
+//
+//	{"width":2,"height":1,"rows":[[{"rune":"█","fg":"#8a2be2","bg":null},{"rune":" ","bg":null}]]}
+//
+// fg is omitted on blank cells, which take the terminal's default. bg is
+// the background as hex, or null where the terminal's shows through, as
+// it does under every cell of a Frame.
+type jsonFrame struct {
+	Width  int          `json:"width"`
+	Height int          `json:"height"`
+	Rows   [][]jsonCell `json:"rows"`
+}
+
+type jsonCell struct {
+	Rune string  `json:"rune"`
+	FG   string  `json:"fg,omitempty"`
+	BG   *string `json:"bg"`
+}
+
+func (f Frame) MarshalJSON() ([]byte, error) {
+	out := jsonFrame{Width: f.Width, Height: f.Height, Rows: make([][]jsonCell, len(f.Cells))}
+	for y, cells := range f.Cells {
+		row := make([]jsonCell, len(cells))
+		for x, c := range cells {
+			row[x] = jsonCell{Rune: string(c.Rune)}
+			if !c.Blank {
+				row[x].FG = c.FG.Hex()
+			}
+		}
+		out.Rows[y] = row
+	}
+	return json.Marshal(out)
+}
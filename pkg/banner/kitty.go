@@ -0,0 +1,53 @@
+package banner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// PNG and the kitty graphics protocol
+//------------------------------------------------------------------------------
+
+// PNG encodes the frame's Image(scale).
+func (f Frame) PNG(scale int) ([]byte, error) {
+	var b bytes.Buffer
+	if err := png.Encode(&b, f.Image(scale)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// kittyChunk is the most base64 the protocol allows per escape.
+const kittyChunk = 4096
+
+// Kitty returns the frame as a PNG sent with the kitty graphics protocol,
+// which kitty, ghostty and WezTerm display at the cursor. q=2 keeps the
+// terminal from answering, which would otherwise show up as typed input.
+func (f Frame) Kitty(scale int) (string, error) {
+	data, err := f.PNG(scale)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for i := 0; i == 0 || i < len(enc); i += kittyChunk {
+		chunk := enc[i:min(i+kittyChunk, len(enc))]
+		more := 0
+		if i+kittyChunk < len(enc) {
+			more = 1
+		}
+		b.WriteString("\x1b_G")
+		if i == 0 {
+			b.WriteString("a=T,f=100,q=2,")
+		}
+		b.WriteString("m=")
+		b.WriteByte(byte('0' + more))
+		b.WriteByte(';')
+		b.WriteString(chunk)
+		b.WriteString("\x1b\\")
+	}
+	return b.String(), nil
+}
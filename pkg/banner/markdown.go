@@ -0,0 +1,111 @@
+package banner
+
+import (
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Markdown code blocks (Discord, Slack)
+//------------------------------------------------------------------------------
+
+// DiscordLimit is the most characters a Discord message may have.
+const DiscordLimit = 2000
+
+// discordColors are the foreground colors Discord's ```ansi blocks show,
+// SGR 30 to 37, as they look in its dark theme.
+var discordColors = [8]Color{
+	{79, 84, 92}, {220, 50, 47}, {133, 153, 0}, {181, 137, 0},
+	{38, 139, 210}, {211, 54, 130}, {42, 161, 152}, {255, 255, 255},
+}
+
+// Markdown wraps the frame in fenced code blocks of at most limit
+// characters each, splitting between rows when one block would be too long
+// for a message; limit <= 0 means no limit. With ansi set the blocks are
+// tagged ```ansi and colored with the eight colors Discord highlights;
+// otherwise they are plain text. Lengths are counted in bytes, which never
+// undercounts; a row too long on its own still gets a block to itself.
+func (f Frame) Markdown(ansi bool, limit int) []string {
+	rows := make([]string, len(f.Cells))
+	for y, cells := range f.Cells {
+		rows[y] = markdownRow(cells, ansi)
+	}
+	fence := strings.Repeat("`", max(3, longestRun(rows, '`')+1))
+	open := fence + "\n"
+	if ansi {
+		open = fence + "ansi\n"
+	}
+	closing := fence
+
+	var blocks []string
+	var b strings.Builder
+	for _, row := range rows {
+		if b.Len() > 0 && limit > 0 && b.Len()+len(row)+1+len(closing) > limit {
+			b.WriteString(closing)
+			blocks = append(blocks, b.String())
+			b.Reset()
+		}
+		if b.Len() == 0 {
+			b.WriteString(open)
+		}
+		b.WriteString(row)
+		b.WriteByte('\n')
+	}
+	if b.Len() > 0 {
+		b.WriteString(closing)
+		blocks = append(blocks, b.String())
+	}
+	return blocks
+}
+
+// markdownRow is one row without trailing blanks, which chat clients strip
+// anyway.
+func markdownRow(cells []Cell, ansi bool) string {
+	end := len(cells)
+	for end > 0 && cells[end-1].Blank {
+		end--
+	}
+	var b strings.Builder
+	code := -1
+	for _, c := range cells[:end] {
+		if ansi && !c.Blank {
+			if k := nearestDiscord(c.FG); k != code {
+				fmt.Fprintf(&b, "\x1b[%dm", 30+k)
+				code = k
+			}
+		}
+		b.WriteRune(c.Rune)
+	}
+	if code >= 0 {
+		b.WriteString(sgrReset)
+	}
+	return b.String()
+}
+
+func nearestDiscord(c Color) int {
+	best, bestD := 0, -1
+	for k, p := range discordColors {
+		if d := colorDistance(c, p); bestD < 0 || d < bestD {
+			best, bestD = k, d
+		}
+	}
+	return best
+}
+
+// longestRun is the length of the longest run of r in any of rows, so the
+// fence can be made longer than any backticks in the art.
+func longestRun(rows []string, r rune) int {
+	longest := 0
+	for _, row := range rows {
+		n := 0
+		for _, c := range row {
+			if c == r {
+				n++
+				longest = max(longest, n)
+			} else {
+				n = 0
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,57 @@
+package banner
+
+//------------------------------------------------------------------------------
+// Functional options
+//------------------------------------------------------------------------------
+
+// Option adjusts Options. Entry points start from DefaultOptions and apply
+// options in order, so later options win.
+type Option func(*Options)
+
+// DefaultOptions matches the viewer's out-of-the-box look.
+func DefaultOptions() Options {
+	return Options{
+		Font:  "standard",
+		Start: Color{138, 43, 226}, // #8A2BE2
+		End:   Color{0, 255, 255},  // #00FFFF
+		Mode:  ModeGlyph,
+	}
+}
+
+// NewOptions resolves opts over the defaults.
+func NewOptions(opts ...Option) Options {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithOptions replaces everything set so far with o.
+func WithOptions(o Options) Option { return func(dst *Options) { *dst = o } }
+
+func WithFont(name string) Option { return func(o *Options) { o.Font = name } }
+
+// WithGradient sets the horizontal gradient endpoints.
+func WithGradient(start, end Color) Option {
+	return func(o *Options) { o.Start, o.End = start, end }
+}
+
+func WithMode(m RenderMode) Option { return func(o *Options) { o.Mode = m } }
+
+// WithHueShift rotates both gradient endpoints by deg degrees.
+func WithHueShift(deg float64) Option { return func(o *Options) { o.HueShift = deg } }
+
+// WithEffect appends effects to the stack. Render applies them once; an
+// AnimationWriter also ticks them between frames.
+func WithEffect(effects ...Effect) Option {
+	return func(o *Options) { o.Effects = append(append([]Effect(nil), o.Effects...), effects...) }
+}
+
+// WithNormalization selects the Unicode form text is brought to before
+// composing; StripMarks makes accented Latin text drawable.
+func WithNormalization(n Normalization) Option { return func(o *Options) { o.Normalize = n } }
+
+// WithSpacing adds n blank columns between letters. Negative values are
+// treated as 0.
+func WithSpacing(n int) Option { return func(o *Options) { o.Spacing = max(0, n) } }
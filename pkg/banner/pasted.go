@@ -0,0 +1,38 @@
+package banner
+
+import "strings"
+
+//------------------------------------------------------------------------------
+// Pre-rendered art
+//------------------------------------------------------------------------------
+
+// ParseArt turns art drawn elsewhere, by figlet, toilet or boxes say, into
+// Art to color like a composed banner. Escape sequences, so any colors it
+// came with, are dropped, tabs expand to 8 columns and blank lines at the
+// end are trimmed.
+func ParseArt(text string) Art {
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		var b strings.Builder
+		rs, col := []rune(strings.TrimSuffix(l, "\r")), 0
+		for i := 0; i < len(rs); i++ {
+			switch r := rs[i]; {
+			case r == '\t':
+				n := 8 - col%8
+				b.WriteString(strings.Repeat(" ", n))
+				col += n
+			case r == 0x1b:
+				i = skipEscape(rs, i)
+			case isControl(r):
+			default:
+				b.WriteRune(r)
+				col++
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return newArt(lines)
+}
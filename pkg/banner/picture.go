@@ -0,0 +1,122 @@
+package banner
+
+import (
+	"bytes"
+	"image"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+//------------------------------------------------------------------------------
+// Images in color half blocks
+//------------------------------------------------------------------------------
+
+// Picture is an image scaled down to terminal cells for showing in color:
+// each cell is two pixels, the upper one drawn as a "▀" and the lower as
+// its background.
+type Picture struct {
+	Width, Height int       // in cells
+	Pixels        [][]Pixel // 2*Height rows of Width
+}
+
+// Pixel is one averaged pixel. Mostly transparent pixels show the
+// terminal's own background.
+type Pixel struct {
+	Color
+	Opaque bool
+}
+
+// NewPicture scales img to width cells; the height follows from the
+// aspect ratio, as for ImageArt, so ImageFit sizes both.
+func NewPicture(img image.Image, width int) Picture {
+	b := img.Bounds()
+	if b.Empty() {
+		return Picture{}
+	}
+	width = max(1, min(width, b.Dx()))
+	height := max(1, b.Dy()*width/b.Dx()/2)
+	p := Picture{Width: width, Height: height, Pixels: make([][]Pixel, 2*height)}
+	rows := 2 * height
+	for y := range rows {
+		y0, y1 := b.Min.Y+y*b.Dy()/rows, b.Min.Y+(y+1)*b.Dy()/rows
+		row := make([]Pixel, width)
+		for x := range width {
+			x0, x1 := b.Min.X+x*b.Dx()/width, b.Min.X+(x+1)*b.Dx()/width
+			row[x] = average(img, image.Rect(x0, y0, max(x1, x0+1), max(y1, y0+1)))
+		}
+		p.Pixels[y] = row
+	}
+	return p
+}
+
+// average is the mean color of img over r, undoing the premultiplied
+// alpha.
+func average(img image.Image, r image.Rectangle) Pixel {
+	var sr, sg, sb, sa uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			sr, sg, sb, sa = sr+uint64(cr), sg+uint64(cg), sb+uint64(cb), sa+uint64(ca)
+		}
+	}
+	n := uint64(r.Dx() * r.Dy())
+	if sa == 0 || sa/n < 0x8000 {
+		return Pixel{}
+	}
+	return Pixel{Color: Color{int(sr * 255 / sa), int(sg * 255 / sa), int(sb * 255 / sa)}, Opaque: true}
+}
+
+// ANSI encodes the picture with truecolor escapes.
+func (p Picture) ANSI() string { return p.encode(termenv.TrueColor) }
+
+// Styled encodes the picture for r's color profile.
+func (p Picture) Styled(r *lipgloss.Renderer) string { return p.encode(r.ColorProfile()) }
+
+// encode writes rows separated by newlines, one escape per change of
+// colors, each row ending in a reset.
+func (p Picture) encode(profile termenv.Profile) string {
+	b := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(b)
+	b.Reset()
+	for y := range p.Height {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		open := ""
+		for x := range p.Width {
+			top, bottom := p.Pixels[2*y][x], p.Pixels[2*y+1][x]
+			glyph, seq := "▀", ""
+			switch {
+			case top.Opaque && bottom.Opaque:
+				seq = sgr(profile, top.Color) + bgSGR(profile, bottom.Color)
+			case top.Opaque:
+				seq = sgr(profile, top.Color)
+			case bottom.Opaque:
+				glyph, seq = "▄", sgr(profile, bottom.Color)
+			default:
+				glyph = " "
+			}
+			if seq != open {
+				if open != "" {
+					b.WriteString(sgrReset)
+				}
+				b.WriteString(seq)
+				open = seq
+			}
+			b.WriteString(glyph)
+		}
+		if open != "" {
+			b.WriteString(sgrReset)
+		}
+	}
+	return b.String()
+}
+
+// bgSGR returns the escape selecting c as background in p.
+func bgSGR(p termenv.Profile, c Color) string {
+	if seq := p.Color(c.Hex()).Sequence(true); seq != "" {
+		return "\x1b[" + seq + "m"
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package banner
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Plasma backgrounds
+//------------------------------------------------------------------------------
+
+// Plasma fills the blank cells around and between the letters with the
+// classic demoscene plasma: a sum of sine waves, flowing with each tick,
+// looked up in a palette and drawn in solid blocks. The frame grows by a
+// margin so the plasma frames the banner.
+type Plasma struct {
+	Palette   []Color // cycled through as the field rises; see PlasmaPalettes
+	Intensity float64 // brightness, 0 to 1; low keeps the letters readable
+	Speed     float64 // field time per tick
+	t         float64
+}
+
+// plasmaPalettes are the named palettes for NewPlasma.
+var plasmaPalettes = map[string][]Color{
+	"fire":    {{0, 0, 0}, {160, 0, 0}, {255, 90, 0}, {255, 200, 40}, {255, 90, 0}, {160, 0, 0}},
+	"ocean":   {{0, 10, 60}, {0, 70, 160}, {0, 170, 220}, {150, 235, 255}, {0, 170, 220}, {0, 70, 160}},
+	"rainbow": {HSV(0, 1, 1), HSV(60, 1, 1), HSV(120, 1, 1), HSV(180, 1, 1), HSV(240, 1, 1), HSV(300, 1, 1)},
+	"toxic":   {{0, 40, 0}, {40, 160, 0}, {180, 255, 40}, {40, 160, 0}},
+}
+
+// PlasmaPalettes lists the palette names in order.
+func PlasmaPalettes() []string {
+	names := make([]string, 0, len(plasmaPalettes))
+	for name := range plasmaPalettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const plasmaMargin = 2 // columns each side; half as many rows
+
+// NewPlasma makes a plasma in a named palette at intensity 0 to 1.
+func NewPlasma(palette string, intensity float64) (*Plasma, error) {
+	p, ok := plasmaPalettes[palette]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette %q (want %s)", palette, strings.Join(PlasmaPalettes(), ", "))
+	}
+	if intensity < 0 || intensity > 1 {
+		return nil, fmt.Errorf("intensity %g out of range (0-1)", intensity)
+	}
+	return &Plasma{Palette: p, Intensity: intensity, Speed: 0.15}, nil
+}
+
+func (p *Plasma) Init(_, _ int) { p.t = 0 }
+
+func (p *Plasma) Tick() { p.t += p.Speed }
+
+// field is the plasma at cell x, y, from 0 to 1. Rows count double, cells
+// being twice as tall as wide.
+func (p *Plasma) field(x, y, w, h int) float64 {
+	fx, fy := float64(x), 2*float64(y)
+	cx, cy := fx-float64(w)/2, fy-float64(h)
+	v := math.Sin(fx/9+p.t) +
+		math.Sin((fy/7+p.t)*0.8) +
+		math.Sin((fx+fy)/12+p.t*0.6) +
+		math.Sin(math.Hypot(cx, cy)/6-p.t*1.3)
+	return (v + 4) / 8
+}
+
+func (p *Plasma) Apply(f Frame) Frame {
+	if len(p.Palette) == 0 {
+		return f
+	}
+	w, h := f.Width+2*plasmaMargin, len(f.Cells)+plasmaMargin
+	left, top := plasmaMargin, plasmaMargin/2
+	cells := make([][]Cell, h)
+	for y := range cells {
+		row := make([]Cell, w)
+		for x := range row {
+			if sy, sx := y-top, x-left; sy >= 0 && sy < len(f.Cells) && sx >= 0 && sx < len(f.Cells[sy]) && !f.Cells[sy][sx].Blank {
+				row[x] = f.Cells[sy][sx]
+				continue
+			}
+			v := p.field(x, y, w, h) * float64(len(p.Palette))
+			i := int(v) % len(p.Palette)
+			c := Lerp(p.Palette[i], p.Palette[(i+1)%len(p.Palette)], v-math.Floor(v))
+			row[x] = Cell{Rune: '█', FG: Lerp(Color{}, c, p.Intensity)}
+		}
+		cells[y] = row
+	}
+	f.Cells, f.Width, f.Height = cells, w, h
+	return f
+}
@@ -0,0 +1,74 @@
+package banner
+
+import (
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Prompt snippets (PS1 for bash, PROMPT for zsh)
+//------------------------------------------------------------------------------
+
+// A prompt has to be told which of its characters take no room, or the
+// shell miscounts the line and the cursor lands in the wrong column once
+// the line is edited: bash wants escapes between \[ and \], zsh between %{
+// and %}. Each function returns an assignment that puts the banner above
+// the existing prompt.
+
+// BashPrompt returns the frame as a bash PS1 assignment.
+func (f Frame) BashPrompt() string {
+	body := promptEscape(f.ANSI(), `\[\e`, `\]`, `\n`, func(r rune) string {
+		// Decoded once as a prompt, then expanded like a double-quoted
+		// string (promptvars), with ! standing for the history number.
+		switch r {
+		case '\\':
+			return `\\\\`
+		case '$':
+			return `\\$`
+		case '`':
+			return "\\\\`"
+		case '!':
+			return "!!"
+		case '\'':
+			return `'\''`
+		}
+		return string(r)
+	})
+	return "PS1='" + body + `\n'"$PS1"` + "\n"
+}
+
+// ZshPrompt returns the frame as a zsh PROMPT assignment.
+func (f Frame) ZshPrompt() string {
+	body := promptEscape(f.ANSI(), `%{\e`, `%}`, `\n`, func(r rune) string {
+		switch r {
+		case '\\':
+			return `\\`
+		case '\'':
+			return `\'`
+		case '%':
+			return "%%"
+		}
+		return string(r)
+	})
+	return "PROMPT=$'" + body + `\n'"$PROMPT"` + "\n"
+}
+
+// promptEscape rewrites ANSI text for a prompt: each escape sequence goes,
+// minus its ESC, between open and close, line breaks become newline and
+// everything else goes through char.
+func promptEscape(ansi, open, close, newline string, char func(rune) string) string {
+	var b strings.Builder
+	rs := []rune(ansi)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; r {
+		case 0x1b:
+			j := skipEscape(rs, i)
+			b.WriteString(open + string(rs[i+1:j+1]) + close)
+			i = j
+		case '\n':
+			b.WriteString(newline)
+		default:
+			b.WriteString(char(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package banner
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+//------------------------------------------------------------------------------
+// Rasterizing (for graphics protocols)
+//------------------------------------------------------------------------------
+
+// Each cell becomes RasterCellW by RasterCellH pixels per unit of scale:
+// FIGlet art assumes cells about twice as tall as they are wide.
+const (
+	RasterCellW = 4
+	RasterCellH = 8
+)
+
+// Image rasterizes the frame on a transparent background, each cell
+// scale*RasterCellW by scale*RasterCellH pixels. The usual FIGlet strokes
+// (_ | / \ - and so on) are drawn as lines, shades as translucent fills and
+// anything else as a solid cell. Colors are interpolated between cell
+// centers, so gradients come out smooth instead of stepped.
+func (f Frame) Image(scale int) *image.NRGBA {
+	scale = max(1, scale)
+	cw, ch := scale*RasterCellW, scale*RasterCellH
+	img := image.NewNRGBA(image.Rect(0, 0, f.Width*cw, f.Height*ch))
+	for y, cells := range f.Cells {
+		for x, c := range cells {
+			if c.Blank {
+				continue
+			}
+			for px := 0; px < cw; px++ {
+				u := (float64(px) + 0.5) / float64(cw)
+				col := smoothColor(cells, x, u)
+				for py := 0; py < ch; py++ {
+					a := ink(c.Rune, u, (float64(py)+0.5)/float64(ch))
+					if a > 0 {
+						img.SetNRGBA(x*cw+px, y*ch+py, color.NRGBA{uint8(col.R), uint8(col.G), uint8(col.B), uint8(a * 255)})
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+// smoothColor is the color at u (0 to 1) across cell x of a row: a blend of
+// its color with the nearer neighbour's, unless that neighbour is blank.
+func smoothColor(cells []Cell, x int, u float64) Color {
+	n := x + 1
+	t := u - 0.5
+	if u < 0.5 {
+		n, t = x-1, 0.5-u
+	}
+	if n < 0 || n >= len(cells) || cells[n].Blank {
+		return cells[x].FG
+	}
+	return Lerp(cells[x].FG, cells[n].FG, t)
+}
+
+// ink is how much of the point (u, v) of a cell, both 0 to 1 from the top
+// left, glyph r covers.
+func ink(r rune, u, v float64) float64 {
+	// Half stroke widths; a cell is twice as tall as wide, so these make
+	// horizontal and vertical strokes equally thick.
+	const tu, tv = 0.2, 0.1
+	on := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	if r >= 0x2800 && r <= 0x28ff {
+		return on(brailleDot(int(r-0x2800), u, v))
+	}
+	switch r {
+	case ' ':
+		return 0
+	case '_':
+		return on(v >= 1-2*tv)
+	case '-', '~':
+		return on(math.Abs(v-0.5) < tv)
+	case '=':
+		return on(math.Abs(v-0.35) < tv*0.7 || math.Abs(v-0.65) < tv*0.7)
+	case '|', '!', '[', ']', 'I', 'l':
+		return on(math.Abs(u-0.5) < tu)
+	case '(':
+		return on(math.Abs(u-0.6-0.4*math.Abs(v-0.5)) < tu)
+	case ')':
+		return on(math.Abs(u-0.4+0.4*math.Abs(v-0.5)) < tu)
+	case '<':
+		return on(math.Abs(u-0.2-1.2*math.Abs(v-0.5)) < tu)
+	case '>':
+		return on(math.Abs(u-0.8+1.2*math.Abs(v-0.5)) < tu)
+	case '/':
+		return on(math.Abs(u-(1-v)) < tu*1.4)
+	case '\\':
+		return on(math.Abs(u-v) < tu*1.4)
+	case '.', ',':
+		return on(math.Abs(u-0.5) < tu && v >= 1-3*tv)
+	case '\'', '`':
+		return on(math.Abs(u-0.5) < tu && v < 3*tv)
+	case '"':
+		return on((math.Abs(u-0.3) < tu*0.7 || math.Abs(u-0.7) < tu*0.7) && v < 3*tv)
+	case ':':
+		return on(math.Abs(u-0.5) < tu && (math.Abs(v-0.3) < tv || math.Abs(v-0.7) < tv))
+	case '·':
+		return on(math.Abs(u-0.5) < tu && math.Abs(v-0.5) < tv)
+	case '░':
+		return 0.25
+	case '▒':
+		return 0.5
+	case '▓':
+		return 0.75
+	case '▀':
+		return on(v < 0.5)
+	case '▄':
+		return on(v >= 0.5)
+	}
+	return 1
+}
+
+// brailleDot reports whether (u, v) falls on a raised dot of the braille
+// pattern with dot bits b (U+2800 + b).
+func brailleDot(b int, u, v float64) bool {
+	col, row := int(u*2), int(v*4)
+	fu, fv := u*2-float64(col), v*4-float64(row)
+	if math.Hypot(fu-0.5, fv-0.5) > 0.35 { // each dot's square is 2 by 2 pixels per scale
+		return false
+	}
+	bit := [2][4]int{{0, 1, 2, 6}, {3, 4, 5, 7}}[col][row]
+	return b&(1<<bit) != 0
+}
@@ -0,0 +1,55 @@
+package banner
+
+import "math"
+
+//------------------------------------------------------------------------------
+// Reflections
+//------------------------------------------------------------------------------
+
+// Reflection mirrors the banner upside down beneath itself, darker and
+// rippling sideways, as if over water. The frame grows to twice its height.
+type Reflection struct {
+	Dim    float64 // how much darker the top of the reflection is, 0 to 1
+	Ripple int     // columns the rows sway
+	Speed  float64 // radians per tick
+	phase  float64
+}
+
+func NewReflection() *Reflection { return &Reflection{Dim: 0.55, Ripple: 1, Speed: 0.3} }
+
+// flipped maps glyphs to their upside-down look, where there is one.
+var flipped = map[rune]rune{
+	'/': '\\', '\\': '/', '_': '-', '^': 'v', 'v': '^', '\'': ',', ',': '\'', '`': ',',
+	'.': '\'', '▀': '▄', '▄': '▀', '╭': '╰', '╰': '╭', '╮': '╯', '╯': '╮', '┌': '└', '└': '┌', '┐': '┘', '┘': '┐',
+}
+
+func (r *Reflection) Init(_, _ int) { r.phase = 0 }
+
+func (r *Reflection) Tick() { r.phase += r.Speed }
+
+func (r *Reflection) Apply(f Frame) Frame {
+	h := len(f.Cells)
+	cells := make([][]Cell, 0, 2*h)
+	cells = append(cells, f.Cells...)
+	for i := range h {
+		src := f.Cells[h-1-i]
+		// Fade the further down the reflection goes.
+		dim := r.Dim + (1-r.Dim)*0.6*float64(i)/float64(max(1, h-1))
+		off := int(math.Round(float64(r.Ripple) * math.Sin(r.phase+0.9*float64(i))))
+		row := make([]Cell, len(src))
+		for x := range row {
+			row[x] = Cell{Rune: ' ', Blank: true}
+			if sx := x - off; sx >= 0 && sx < len(src) && !src[sx].Blank {
+				c := src[sx]
+				if m, ok := flipped[c.Rune]; ok {
+					c.Rune = m
+				}
+				c.FG = Lerp(c.FG, Color{}, dim)
+				row[x] = c
+			}
+		}
+		cells = append(cells, row)
+	}
+	f.Cells, f.Height = cells, len(cells)
+	return f
+}
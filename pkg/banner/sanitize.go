@@ -0,0 +1,134 @@
+package banner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//------------------------------------------------------------------------------
+// Input sanitizing and normalization
+//------------------------------------------------------------------------------
+
+// Sanitize makes text safe to compose and to echo to a terminal: tabs and
+// line breaks become spaces, and escape sequences and other control
+// characters are removed. It reports whether anything was changed.
+func Sanitize(text string) (string, bool) {
+	clean := true
+	for _, r := range text {
+		if isControl(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return text, false
+	}
+
+	var b strings.Builder
+	rs := []rune(text)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '\t', r == '\n', r == '\r':
+			b.WriteByte(' ')
+		case r == 0x1b: // ESC: skip the whole sequence
+			i = skipEscape(rs, i)
+		case isControl(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), true
+}
+
+// isControl reports C0 and C1 control characters and DEL.
+func isControl(r rune) bool { return r < ' ' || r >= 0x7f && r < 0xa0 }
+
+// skipEscape returns the index of the last rune of the escape sequence
+// starting at rs[i]: CSI up to its final byte, OSC and other strings up to
+// BEL or ST, or else ESC and the one character after it.
+func skipEscape(rs []rune, i int) int {
+	if i+1 >= len(rs) {
+		return i
+	}
+	switch rs[i+1] {
+	case '[': // CSI: parameters, then a final byte in @..~
+		for j := i + 2; j < len(rs); j++ {
+			if rs[j] >= '@' && rs[j] <= '~' {
+				return j
+			}
+		}
+		return len(rs) - 1
+	case ']', 'P', '_', '^', 'X': // OSC, DCS, APC, PM, SOS: up to BEL or ESC \
+		for j := i + 2; j < len(rs); j++ {
+			if rs[j] == 0x07 {
+				return j
+			}
+			if rs[j] == 0x1b && j+1 < len(rs) && rs[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(rs) - 1
+	}
+	return i + 1
+}
+
+// checkDrawable rejects text go-figure can't draw: it only has glyphs for
+// printable ASCII and exits the whole program on anything else.
+func checkDrawable(text string) error {
+	for _, r := range text {
+		if r < ' ' || r > '~' {
+			return fmt.Errorf("can't draw %q: fonts only cover printable ASCII", r)
+		}
+	}
+	return nil
+}
+
+// Normalization is the Unicode form text is brought to before composing,
+// so that composed and decomposed accents compose the same.
+type Normalization int
+
+const (
+	NFC        Normalization = iota // é as one rune (default)
+	NFD                             // é as e plus a combining accent
+	StripMarks                      // decomposed, then accents dropped: é becomes e
+)
+
+var normalizationNames = []string{"nfc", "nfd", "strip"}
+
+func (n Normalization) String() string {
+	if n < 0 || int(n) >= len(normalizationNames) {
+		return fmt.Sprintf("Normalization(%d)", int(n))
+	}
+	return normalizationNames[n]
+}
+
+// ParseNormalization looks a normalization up by name: nfc, nfd or strip.
+func ParseNormalization(name string) (Normalization, error) {
+	for i, s := range normalizationNames {
+		if strings.EqualFold(name, s) {
+			return Normalization(i), nil
+		}
+	}
+	return NFC, fmt.Errorf("unknown normalization %q (want nfc, nfd or strip)", name)
+}
+
+// Normalize brings text to form n.
+func Normalize(text string, n Normalization) string {
+	switch n {
+	case NFD:
+		return norm.NFD.String(text)
+	case StripMarks:
+		var b strings.Builder
+		for _, r := range norm.NFD.String(text) {
+			if !unicode.Is(unicode.Mn, r) {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+	return norm.NFC.String(text)
+}
@@ -0,0 +1,57 @@
+package banner
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"café ☕", "café ☕"},
+		{"a\tb\nc\r\nd", "a b c  d"},
+		{"red \x1b[31mtext\x1b[0m", "red text"},
+		{"\x1b]0;title\x07after", "after"},
+		{"\x1b]8;;http://x\x1b\\link", "link"},
+		{"bell\x07 del\x7f c1\u0085", "bell del c1"},
+		{"esc at end\x1b", "esc at end"},
+		{"\x1bcreset", "reset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, changed := Sanitize(tt.in)
+			if got != tt.want || changed != (tt.in != tt.want) {
+				t.Errorf("Sanitize(%q) = %q, %v; want %q", tt.in, got, changed, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	const composed, decomposed = "caf\u00e9", "cafe\u0301"
+	tests := []struct {
+		name, in, want string
+	}{
+		{"nfc", decomposed, composed},
+		{"nfc", composed, composed},
+		{"nfd", composed, decomposed},
+		{"strip", composed, "cafe"},
+		{"strip", decomposed, "cafe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.in, func(t *testing.T) {
+			n, err := ParseNormalization(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.String() != tt.name {
+				t.Errorf("String() = %q", n.String())
+			}
+			if got := Normalize(tt.in, n); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+	if _, err := ParseNormalization("nfkc"); err == nil {
+		t.Error("ParseNormalization(nfkc) succeeded")
+	}
+}
@@ -0,0 +1,76 @@
+package banner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// .ans files and SAUCE records
+//------------------------------------------------------------------------------
+
+// Sauce is the metadata of a SAUCE record, the trailer ANSI art archives
+// and viewers read a piece's title, credits and size from.
+type Sauce struct {
+	Title  string // at most 35 characters
+	Author string // at most 20
+	Group  string // at most 20
+	Date   time.Time
+}
+
+// cp437 maps the non-ASCII runes the render modes use to code page 437,
+// the encoding .ans files are in.
+var cp437 = map[rune]byte{'█': 0xdb, '▓': 0xb2, '▒': 0xb1, '░': 0xb0, '▀': 0xdf, '▄': 0xdc, '·': 0xfa}
+
+// ANS returns the frame as an .ans file: CRLF line ends, text in code page
+// 437 (runes it lacks become '?') and, with s set, a SAUCE record giving
+// the size so viewers don't wrap it at 80 columns.
+func (f Frame) ANS(s *Sauce) []byte {
+	var b bytes.Buffer
+	for _, r := range strings.ReplaceAll(f.ANSI(), "\n", "\r\n") {
+		switch c, ok := cp437[r]; {
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case ok:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	if s == nil {
+		return b.Bytes()
+	}
+	size := b.Len()
+	b.WriteByte(0x1a) // EOF: DOS viewers stop here
+	b.WriteString("SAUCE00")
+	b.WriteString(sauceField(s.Title, 35))
+	b.WriteString(sauceField(s.Author, 20))
+	b.WriteString(sauceField(s.Group, 20))
+	b.WriteString(s.Date.Format("20060102"))
+	binary.Write(&b, binary.LittleEndian, struct {
+		FileSize           uint32
+		DataType, FileType uint8 // 1, 1: character, ANSi
+		Width, Lines       uint16
+		TInfo3, TInfo4     uint16
+		Comments, Flags    uint8
+		Font               [22]byte
+	}{FileSize: uint32(size), DataType: 1, FileType: 1, Width: uint16(f.Width), Lines: uint16(f.Height)})
+	return b.Bytes()
+}
+
+// sauceField pads or cuts s to n ASCII characters.
+func sauceField(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		if b.Len() == n {
+			break
+		}
+		if r < ' ' || r > '~' {
+			r = '?'
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + strings.Repeat(" ", n-b.Len())
+}
@@ -0,0 +1,53 @@
+package banner
+
+import (
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Script exports (Bash, PowerShell, Python)
+//------------------------------------------------------------------------------
+
+// The scripts print the frame in 24-bit color, one statement per row, so
+// projects in other languages can show the banner from a startup script.
+
+const scriptComment = "Banner generated by ascii-viewer."
+
+// Bash returns a Bash script printing the frame with echo -e.
+func (f Frame) Bash() string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n# " + scriptComment + "\n")
+	for _, row := range strings.Split(f.ANSI(), "\n") {
+		row = strings.ReplaceAll(row, `\`, `\\`) // echo -e would read them as escapes
+		row = strings.ReplaceAll(row, "\x1b", `\e`)
+		row = strings.ReplaceAll(row, "'", `'\''`)
+		b.WriteString("echo -e '" + row + "'\n")
+	}
+	return b.String()
+}
+
+// PowerShell returns a PowerShell script printing the frame with
+// Write-Host. ESC comes from [char]27, since Windows PowerShell 5.1 has no
+// `e escape.
+func (f Frame) PowerShell() string {
+	var b strings.Builder
+	b.WriteString("# " + scriptComment + "\n$esc = [char]27\n")
+	quote := strings.NewReplacer("`", "``", "$", "`$", `"`, "`\"", "\x1b", "${esc}")
+	for _, row := range strings.Split(f.ANSI(), "\n") {
+		b.WriteString(`Write-Host "` + quote.Replace(row) + "\"\n")
+	}
+	return b.String()
+}
+
+// Python returns a Python snippet defining the frame as BANNER and printing
+// it when run as a script.
+func (f Frame) Python() string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env python3\n# " + scriptComment + "\n\nBANNER = \"\\n\".join([\n")
+	for _, row := range strings.Split(f.ANSI(), "\n") {
+		b.WriteString("    " + strconv.Quote(row) + ",\n") // Go's escapes are valid Python
+	}
+	b.WriteString("])\n\nif __name__ == \"__main__\":\n    print(BANNER)\n")
+	return b.String()
+}
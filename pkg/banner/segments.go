@@ -0,0 +1,173 @@
+package banner
+
+import (
+	"fmt"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Seven-segment digits (the "segments" fonts)
+//------------------------------------------------------------------------------
+
+// SegmentFonts are drawn by a seven-segment renderer instead of FIGlet, in
+// solid blocks, for clocks and timers. The number is the stroke thickness;
+// the digits grow with it.
+var SegmentFonts = []string{"segments", "segments2", "segments3"}
+
+// Segment bits: a is the top bar, then clockwise, g is the middle bar.
+const (
+	segA = 1 << iota
+	segB
+	segC
+	segD
+	segE
+	segF
+	segG
+)
+
+// segmentGlyphs maps the characters seven segments can show. Letters only
+// have one shape; the other case is looked up if one is missing.
+var segmentGlyphs = map[rune]int{
+	'0': segA | segB | segC | segD | segE | segF,
+	'1': segB | segC,
+	'2': segA | segB | segD | segE | segG,
+	'3': segA | segB | segC | segD | segG,
+	'4': segB | segC | segF | segG,
+	'5': segA | segC | segD | segF | segG,
+	'6': segA | segC | segD | segE | segF | segG,
+	'7': segA | segB | segC,
+	'8': segA | segB | segC | segD | segE | segF | segG,
+	'9': segA | segB | segC | segD | segF | segG,
+	'A': segA | segB | segC | segE | segF | segG,
+	'b': segC | segD | segE | segF | segG,
+	'C': segA | segD | segE | segF,
+	'c': segD | segE | segG,
+	'd': segB | segC | segD | segE | segG,
+	'E': segA | segD | segE | segF | segG,
+	'F': segA | segE | segF | segG,
+	'G': segA | segC | segD | segE | segF,
+	'H': segB | segC | segE | segF | segG,
+	'h': segC | segE | segF | segG,
+	'I': segE | segF,
+	'J': segB | segC | segD | segE,
+	'L': segD | segE | segF,
+	'n': segC | segE | segG,
+	'o': segC | segD | segE | segG,
+	'P': segA | segB | segE | segF | segG,
+	'q': segA | segB | segC | segF | segG,
+	'r': segE | segG,
+	'S': segA | segC | segD | segF | segG,
+	't': segD | segE | segF | segG,
+	'U': segB | segC | segD | segE | segF,
+	'u': segC | segD | segE,
+	'y': segB | segC | segD | segF | segG,
+	'-': segG,
+	'_': segD,
+	' ': 0,
+}
+
+const segmentInk = '█'
+
+// segmentThickness reports whether font is a segment font and its stroke
+// thickness.
+func segmentThickness(font string) (int, bool) {
+	for i, f := range SegmentFonts {
+		if f == font {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// segmentLines draws txt in seven segments t cells thick (2t columns wide
+// on the sides, since cells are about twice as tall as wide). Besides the
+// segmentGlyphs, ':' and '.' are drawn as dots.
+func segmentLines(txt string, t int) ([]string, error) {
+	const barLen, sideLen = 4, 2 // inner lengths, whatever the thickness
+	side := 2 * t
+	width, height := 2*side+barLen, 3*t+2*sideLen
+	mid := t + sideLen // first row of the middle bar
+
+	rows := make([][]rune, height)
+	for i, r := range []rune(txt) {
+		if i > 0 {
+			appendBlank(rows, t)
+		}
+		switch r {
+		case ':':
+			dot(rows, side, t+(sideLen-t+1)/2, mid+t+(sideLen-t+1)/2, t)
+			continue
+		case '.':
+			dot(rows, side, height-t, -1, t)
+			continue
+		}
+		segs, ok := segmentGlyph(r)
+		if !ok {
+			return nil, fmt.Errorf("can't draw %q in seven segments", r)
+		}
+		on := func(mask int) bool { return segs&mask != 0 }
+		for y := range height {
+			row := make([]rune, width)
+			for x := range width {
+				left, right := x < side, x >= width-side
+				top, bottom := y < mid, y >= mid+t
+				var lit bool
+				switch {
+				case y < t:
+					lit = on(segA) || left && on(segF) || right && on(segB)
+				case y >= height-t:
+					lit = on(segD) || left && on(segE) || right && on(segC)
+				case !top && !bottom:
+					lit = on(segG) || left && (on(segF) || on(segE)) || right && (on(segB) || on(segC))
+				case top:
+					lit = left && on(segF) || right && on(segB)
+				default:
+					lit = left && on(segE) || right && on(segC)
+				}
+				row[x] = ' '
+				if lit {
+					row[x] = segmentInk
+				}
+			}
+			rows[y] = append(rows[y], row...)
+		}
+	}
+	lines := make([]string, height)
+	for y, r := range rows {
+		lines[y] = strings.TrimRight(string(r), " ")
+	}
+	return lines, nil
+}
+
+// segmentGlyph looks r up in either case.
+func segmentGlyph(r rune) (int, bool) {
+	if segs, ok := segmentGlyphs[r]; ok {
+		return segs, true
+	}
+	for _, alt := range []string{strings.ToUpper(string(r)), strings.ToLower(string(r))} {
+		if segs, ok := segmentGlyphs[[]rune(alt)[0]]; ok {
+			return segs, true
+		}
+	}
+	return 0, false
+}
+
+// appendBlank adds n blank columns to every row.
+func appendBlank(rows [][]rune, n int) {
+	for y := range rows {
+		rows[y] = append(rows[y], []rune(strings.Repeat(" ", n))...)
+	}
+}
+
+// dot adds a column width wide with t-row dots at rows y1 and y2 (if not
+// negative).
+func dot(rows [][]rune, width, y1, y2, t int) {
+	for y := range rows {
+		ink := y >= y1 && y < y1+t || y2 >= 0 && y >= y2 && y < y2+t
+		c := ' '
+		if ink {
+			c = segmentInk
+		}
+		rows[y] = append(rows[y], []rune(strings.Repeat(string(c), width))...)
+	}
+}
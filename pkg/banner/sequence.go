@@ -0,0 +1,94 @@
+package banner
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Hand-made animations
+//------------------------------------------------------------------------------
+
+// LoadSequence reads the frames of a hand-made animation: every file in a
+// directory, in name order, or one file split at lines reading just sep.
+// Frames are plain text, or .ans files taken without their colors. All are
+// padded to the size of the largest, so effects run on through them.
+func LoadSequence(path, sep string) ([]Art, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var frames []Art
+	if info.IsDir() {
+		entries, err := os.ReadDir(path) // sorted by name
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, parseFrame(e.Name(), data))
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range splitFrames(data, sep) {
+			frames = append(frames, parseFrame(path, chunk))
+		}
+	}
+	if len(frames) == 0 {
+		return nil, errors.New("no frames")
+	}
+	return padFrames(frames), nil
+}
+
+func parseFrame(name string, data []byte) Art {
+	if strings.EqualFold(filepath.Ext(name), ".ans") {
+		return ParseANS(data).Art()
+	}
+	return ParseArt(string(data))
+}
+
+// splitFrames cuts data at each line equal to sep, dropping the lines.
+func splitFrames(data []byte, sep string) [][]byte {
+	var chunks [][]byte
+	var cur []byte
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if string(bytes.TrimRight(line, "\r\n")) == sep {
+			chunks, cur = append(chunks, cur), nil
+			continue
+		}
+		cur = append(cur, line...)
+	}
+	if len(bytes.TrimSpace(cur)) > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks
+}
+
+// padFrames grows every frame to the widest and tallest of them.
+func padFrames(frames []Art) []Art {
+	w, h := 0, 0
+	for _, f := range frames {
+		w, h = max(w, f.Width), max(h, f.height())
+	}
+	for i, f := range frames {
+		for len(f.Rows) < h {
+			f.Rows = append(f.Rows, nil)
+		}
+		f.Width = w
+		f.pad()
+		frames[i] = f
+	}
+	return frames
+}
@@ -0,0 +1,131 @@
+package banner
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Sixel graphics
+//------------------------------------------------------------------------------
+
+// Sixel encodes the frame's Image(scale) as a DEC sixel graphic, for
+// terminals such as foot, WezTerm, mlterm and xterm -ti vt340. The
+// background stays transparent; translucent shade pixels are drawn solid,
+// since sixel has no alpha.
+func (f Frame) Sixel(scale int) string { return encodeSixel(f.Image(scale)) }
+
+// sixelColors is the palette size. 256 registers are the most terminals
+// offer.
+const sixelColors = 256
+
+func encodeSixel(img *image.NRGBA) string {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	palette, index := sixelPalette(img)
+
+	var b strings.Builder
+	b.WriteString("\x1bP0;1;0q") // P2=1: pixels not drawn stay transparent
+	fmt.Fprintf(&b, `"1;1;%d;%d`, w, h)
+	for i, c := range palette {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, percent(c.R), percent(c.G), percent(c.B))
+	}
+	used := make([]bool, len(palette))
+	for y0 := 0; y0 < h; y0 += 6 {
+		clear(used)
+		for y := y0; y < min(y0+6, h); y++ {
+			for x := 0; x < w; x++ {
+				if i := index[y*w+x]; i >= 0 {
+					used[i] = true
+				}
+			}
+		}
+		for i := range palette {
+			if !used[i] {
+				continue
+			}
+			fmt.Fprintf(&b, "#%d", i)
+			sixelRow(&b, index, w, h, y0, int16(i))
+			b.WriteByte('$') // back to the start of the band for the next color
+		}
+		b.WriteByte('-') // next band
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// sixelRow writes the band starting at row y0 for palette entry i,
+// run-length encoded and without trailing empty columns.
+func sixelRow(b *strings.Builder, index []int16, w, h, y0 int, i int16) {
+	var last byte
+	n := 0
+	flush := func() {
+		switch {
+		case n > 3:
+			fmt.Fprintf(b, "!%d%c", n, last)
+		case n > 0:
+			b.WriteString(strings.Repeat(string(last), n))
+		}
+	}
+	end := 0
+	bits := make([]byte, w)
+	for x := 0; x < w; x++ {
+		for k := 0; k < 6 && y0+k < h; k++ {
+			if index[(y0+k)*w+x] == i {
+				bits[x] |= 1 << k
+			}
+		}
+		if bits[x] != 0 {
+			end = x + 1
+		}
+	}
+	for _, v := range bits[:end] {
+		ch := '?' + v
+		if ch != last {
+			flush()
+			last, n = ch, 0
+		}
+		n++
+	}
+	flush()
+}
+
+// sixelPalette picks at most sixelColors colors for the visible pixels of
+// img, dropping low bits until they fit, and indexes each pixel into it
+// (-1 for transparent ones).
+func sixelPalette(img *image.NRGBA) ([]Color, []int16) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	index := make([]int16, w*h)
+	for shift := uint(0); ; shift++ {
+		var palette []Color
+		seen := map[Color]int16{}
+		fits := true
+		for y := 0; y < h && fits; y++ {
+			for x := 0; x < w; x++ {
+				p := img.NRGBAAt(x, y)
+				if p.A == 0 {
+					index[y*w+x] = -1
+					continue
+				}
+				c := Color{int(p.R >> shift << shift), int(p.G >> shift << shift), int(p.B >> shift << shift)}
+				i, ok := seen[c]
+				if !ok {
+					if len(palette) == sixelColors {
+						fits = false
+						break
+					}
+					i = int16(len(palette))
+					seen[c] = i
+					palette = append(palette, c)
+				}
+				index[y*w+x] = i
+			}
+		}
+		if fits {
+			return palette, index
+		}
+	}
+}
+
+// percent converts a color channel to sixel's 0-100 scale.
+func percent(v int) int { return (v*100 + 127) / 255 }
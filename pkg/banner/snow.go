@@ -0,0 +1,121 @@
+package banner
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+//------------------------------------------------------------------------------
+// Snowfall
+//------------------------------------------------------------------------------
+
+// Snow drifts flakes down past the banner from Headroom rows above it. A
+// flake that lands on top of a letter, or on the bottom row, settles there
+// and stays; one landing on settled snow melts. Flakes only show in blank
+// cells, so they pass behind the letters. The same seed falls the same way,
+// so exports are repeatable.
+type Snow struct {
+	Headroom int     // rows added above the banner for flakes to fall through
+	Density  float64 // chance per column per tick of a new flake
+	Speed    float64 // rows per tick
+
+	rng     *rand.Rand
+	flakes  []flake
+	settled map[[2]int]bool // cells with snow on, in padded frame coordinates
+	ink     [][]bool        // the last frame's letters, padded
+	w, h    int
+}
+
+type flake struct {
+	x, y, phase float64
+}
+
+var (
+	snowColor    = Color{235, 240, 255}
+	settledColor = Color{210, 225, 255}
+)
+
+func NewSnow() *Snow { return &Snow{Headroom: 3, Density: 0.03, Speed: 0.35} }
+
+func (s *Snow) Init(width, height int) {
+	s.rng = rand.New(rand.NewPCG(1, 2))
+	s.flakes, s.settled, s.ink = nil, map[[2]int]bool{}, nil
+	s.w, s.h = width, height+s.Headroom
+}
+
+func (s *Snow) Tick() {
+	if s.rng == nil {
+		s.Init(0, 0)
+	}
+	for x := range s.w {
+		if s.rng.Float64() < s.Density {
+			s.flakes = append(s.flakes, flake{x: float64(x), phase: s.rng.Float64() * 2 * math.Pi})
+		}
+	}
+	kept := s.flakes[:0]
+	for _, f := range s.flakes {
+		f.y += s.Speed
+		f.phase += 0.2
+		f.x = min(max(0, f.x+0.3*math.Sin(f.phase)), float64(s.w-1))
+		x, y := int(math.Round(f.x)), int(f.y)
+		switch {
+		case y >= s.h-1 || s.inked(x, y+1):
+			if !s.settled[[2]int{x, y}] && !s.inked(x, y) {
+				s.settled[[2]int{x, y}] = true
+			}
+		case s.settled[[2]int{x, y + 1}]:
+			// melts on the snow already there
+		default:
+			kept = append(kept, f)
+		}
+	}
+	s.flakes = kept
+}
+
+func (s *Snow) inked(x, y int) bool {
+	return y >= 0 && y < len(s.ink) && x >= 0 && x < len(s.ink[y]) && s.ink[y][x]
+}
+
+func (s *Snow) Apply(f Frame) Frame {
+	cells := make([][]Cell, s.Headroom, s.Headroom+len(f.Cells))
+	for y := range cells {
+		cells[y] = make([]Cell, f.Width)
+		for x := range cells[y] {
+			cells[y][x] = Cell{Rune: ' ', Blank: true}
+		}
+	}
+	cells = append(cells, f.Cells...)
+	s.ink = make([][]bool, len(cells))
+	for y, row := range cells {
+		s.ink[y] = make([]bool, len(row))
+		for x, c := range row {
+			s.ink[y][x] = !c.Blank && c.Rune != ' '
+		}
+	}
+
+	// Settled snow and flakes go on copies of the rows they touch, so the
+	// frame the letters came in is left alone.
+	touched := map[int]bool{}
+	put := func(x, y int, c Cell) {
+		if y < 0 || y >= len(cells) || x < 0 || x >= len(cells[y]) || !cells[y][x].Blank {
+			return
+		}
+		if y >= s.Headroom && !touched[y] {
+			cells[y] = append([]Cell(nil), cells[y]...)
+			touched[y] = true
+		}
+		cells[y][x] = c
+	}
+	for p := range s.settled {
+		put(p[0], p[1], Cell{Rune: '▄', FG: settledColor})
+	}
+	for i, fl := range s.flakes {
+		r := '*'
+		if i%3 == 0 {
+			r = '.'
+		}
+		put(int(math.Round(fl.x)), int(fl.y), Cell{Rune: r, FG: snowColor})
+	}
+	f.Cells, f.Height = cells, len(cells)
+	return f
+}
@@ -0,0 +1,100 @@
+package banner
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Gradient stops
+//------------------------------------------------------------------------------
+
+// Stop is a color partway along the gradient, between Start at 0 and End
+// at 1.
+type Stop struct {
+	At    float64 // position, 0 to 1
+	Color Color
+}
+
+// WithStops replaces the colors between the gradient endpoints.
+func WithStops(stops ...Stop) Option {
+	return func(o *Options) { o.Stops = append([]Stop(nil), stops...) }
+}
+
+// ParseStops reads stops like "#ff0000@50, #00ff00@75": a hex color, an @
+// and a position in percent, separated by commas. The empty string is no
+// stops. The result is in position order.
+func ParseStops(s string) ([]Stop, error) {
+	var stops []Stop
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		color, at, ok := strings.Cut(part, "@")
+		c, okColor := ParseHex(color)
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(at), "%"), 64)
+		if !ok || !okColor || err != nil || pct < 0 || pct > 100 {
+			return nil, fmt.Errorf("gradient stop %q: want #RRGGBB@PERCENT", part)
+		}
+		stops = append(stops, Stop{At: pct / 100, Color: c})
+	}
+	SortStops(stops)
+	return stops, nil
+}
+
+// FormatStops is the inverse of ParseStops, positions to a tenth of a
+// percent.
+func FormatStops(stops []Stop) string {
+	parts := make([]string, len(stops))
+	for i, s := range stops {
+		parts[i] = s.Color.Hex() + "@" + strconv.FormatFloat(math.Round(s.At*1000)/10, 'f', -1, 64)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SortStops puts stops in position order, keeping the order of stops at
+// the same position.
+func SortStops(stops []Stop) {
+	slices.SortStableFunc(stops, func(a, b Stop) int {
+		switch {
+		case a.At < b.At:
+			return -1
+		case a.At > b.At:
+			return 1
+		}
+		return 0
+	})
+}
+
+// Blend is the gradient's color at t in [0,1]: start, then each of stops,
+// which must be in position order, then end.
+func Blend(start, end Color, stops []Stop, t float64) Color {
+	from := Stop{0, start}
+	for i := 0; i <= len(stops); i++ {
+		to := Stop{1, end}
+		if i < len(stops) {
+			to = stops[i]
+		}
+		if t <= to.At {
+			if to.At <= from.At {
+				return to.Color
+			}
+			return Lerp(from.Color, to.Color, (t-from.At)/(to.At-from.At))
+		}
+		from = to
+	}
+	return end
+}
+
+// rotateStops is stops with each color's hue turned by deg degrees.
+func rotateStops(stops []Stop, deg float64) []Stop {
+	out := make([]Stop, len(stops))
+	for i, s := range stops {
+		out[i] = Stop{s.At, RotateHue(s.Color, deg)}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package banner
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseStops(t *testing.T) {
+	red, green := Color{R: 255}, Color{G: 255}
+	tests := []struct {
+		in   string
+		want []Stop
+		ok   bool
+	}{
+		{"", nil, true},
+		{" , ", nil, true},
+		{"#ff0000@50", []Stop{{0.5, red}}, true},
+		{"#00ff00@75, #ff0000@25%", []Stop{{0.25, red}, {0.75, green}}, true},
+		{"#ff0000@0,#00ff00@100", []Stop{{0, red}, {1, green}}, true},
+		{"#ff0000", nil, false},
+		{"red@50", nil, false},
+		{"#ff0000@", nil, false},
+		{"#ff0000@-1", nil, false},
+		{"#ff0000@101", nil, false},
+		{"#ff0000@half", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseStops(tt.in)
+			if (err == nil) != tt.ok || !slices.Equal(got, tt.want) {
+				t.Errorf("ParseStops(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatStops(t *testing.T) {
+	stops := []Stop{{0.25, Color{R: 255}}, {1.0 / 3, Color{B: 255}}}
+	s := FormatStops(stops)
+	if want := "#ff0000@25, #0000ff@33.3"; s != want {
+		t.Fatalf("FormatStops = %q, want %q", s, want)
+	}
+	back, err := ParseStops(s)
+	if err != nil || len(back) != 2 || back[0] != stops[0] || FormatStops(back) != s {
+		t.Errorf("ParseStops(%q) = %v, %v", s, back, err)
+	}
+}
+
+func TestBlend(t *testing.T) {
+	black, white, red := Color{}, Color{255, 255, 255}, Color{R: 255}
+	stops := []Stop{{0.5, red}}
+	tests := []struct {
+		t    float64
+		want Color
+	}{
+		{0, black},
+		{0.25, Lerp(black, red, 0.5)},
+		{0.5, red},
+		{0.75, Lerp(red, white, 0.5)},
+		{1, white},
+	}
+	for _, tt := range tests {
+		if got := Blend(black, white, stops, tt.t); got != tt.want {
+			t.Errorf("Blend at %v = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
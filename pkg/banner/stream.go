@@ -0,0 +1,138 @@
+package banner
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+//------------------------------------------------------------------------------
+// Streaming animation
+//------------------------------------------------------------------------------
+
+const (
+	csiHome       = "\x1b[H"
+	csiClear      = "\x1b[2J"
+	csiHideCursor = "\x1b[?25l"
+	csiShowCursor = "\x1b[?25h"
+)
+
+// AnimationWriter plays animated frames to any io.Writer, outside Bubble
+// Tea: a pipe, a file, or a serial sign. The effects from WithEffect tick
+// once per frame. Each frame starts with cursor-home so a terminal redraws
+// in place.
+type AnimationWriter struct {
+	FPS    float64 // frames per second; <= 0 means 15
+	Frames int     // stop after this many frames; 0 runs until ctx is done
+	Width  int     // optional canvas size to center on
+	Height int
+
+	// Renderer styles the frames for a color profile; nil means 24-bit
+	// color, as ANSI does.
+	Renderer *lipgloss.Renderer
+
+	// Sequence, if set, plays in place of the art, each of its frames held
+	// for Hold frames (at least one): a hand-made animation, all frames the
+	// size of the art.
+	Sequence []Art
+	Hold     int
+
+	n     int // frames rendered so far
+	w     io.Writer
+	art   Art
+	opts  Options
+	frame Frame // reused between frames
+}
+
+// NewAnimationWriter streams art colored with opts to w.
+func NewAnimationWriter(w io.Writer, art Art, opts ...Option) *AnimationWriter {
+	a := &AnimationWriter{FPS: 15, w: w, art: art, opts: NewOptions(opts...)}
+	for _, e := range a.opts.Effects {
+		e.Init(art.Width, art.height())
+	}
+	return a
+}
+
+// Next renders the current frame, without control sequences, then ticks
+// the effects.
+func (a *AnimationWriter) Next() string {
+	f := a.next()
+	if a.Renderer != nil {
+		return f.Styled(a.Renderer)
+	}
+	return f.ANSI()
+}
+
+// Capture renders the next n frames for an animated image. Unlike Next's,
+// the frames are copies and stay valid.
+func (a *AnimationWriter) Capture(n int) []Frame {
+	frames := make([]Frame, n)
+	for i := range frames {
+		frames[i] = a.next().clone()
+	}
+	return frames
+}
+
+// next colors and places the current frame, then ticks the effects. The
+// frame may share a.frame's storage.
+func (a *AnimationWriter) next() Frame {
+	art := a.art
+	if len(a.Sequence) > 0 {
+		art = a.Sequence[a.n/max(1, a.Hold)%len(a.Sequence)]
+	}
+	a.n++
+	art.ColorizeInto(&a.frame, a.opts)
+	f := ApplyEffects(a.frame, a.opts.Effects)
+	if a.Width > 0 && a.Height > 0 {
+		f = f.Place(a.Width, a.Height)
+	}
+	fps := a.FPS
+	if fps <= 0 {
+		fps = 15
+	}
+	for _, e := range a.opts.Effects {
+		if t, ok := e.(Timed); ok {
+			t.SetTickDuration(time.Duration(float64(time.Second) / fps))
+		}
+		e.Tick()
+	}
+	return f
+}
+
+func (f Frame) clone() Frame {
+	out := f
+	out.Cells = make([][]Cell, len(f.Cells))
+	for y, row := range f.Cells {
+		out.Cells[y] = append([]Cell(nil), row...)
+	}
+	return out
+}
+
+// Run writes frames at FPS until Frames are done or ctx is cancelled. The
+// cursor is hidden while playing and restored afterwards.
+func (a *AnimationWriter) Run(ctx context.Context) error {
+	fps := a.FPS
+	if fps <= 0 {
+		fps = 15
+	}
+	if _, err := io.WriteString(a.w, csiHideCursor+csiClear); err != nil {
+		return err
+	}
+	defer io.WriteString(a.w, csiShowCursor+"\n")
+
+	t := time.NewTicker(time.Duration(float64(time.Second) / fps))
+	defer t.Stop()
+	for n := 0; a.Frames == 0 || n < a.Frames; n++ {
+		if _, err := io.WriteString(a.w, csiHome+a.Next()); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package banner
+
+//------------------------------------------------------------------------------
+// Trimming and width limits for exports
+//------------------------------------------------------------------------------
+
+// These work on cells rather than on encoded text, so a cut never lands
+// inside an escape sequence. Rows of the result may differ in length; Width
+// is the longest.
+
+// TrimRight drops the blank cells at the end of each row.
+func (f Frame) TrimRight() Frame {
+	out := Frame{Cells: make([][]Cell, len(f.Cells)), Height: f.Height}
+	for y, row := range f.Cells {
+		end := len(row)
+		for end > 0 && row[end-1].Blank {
+			end--
+		}
+		out.Cells[y] = row[:end]
+		out.Width = max(out.Width, end)
+	}
+	return out
+}
+
+// Truncate cuts every row to at most width cells.
+func (f Frame) Truncate(width int) Frame {
+	if width <= 0 || f.Width <= width {
+		return f
+	}
+	out := Frame{Cells: make([][]Cell, len(f.Cells)), Width: width, Height: f.Height}
+	for y, row := range f.Cells {
+		out.Cells[y] = row[:min(width, len(row))]
+	}
+	return out
+}
+
+// Wrap breaks the frame into bands at most width cells wide, stacked top
+// to bottom. Bands end after the last all-blank column that fits, so
+// letters stay whole, unless a letter is wider than width by itself.
+func (f Frame) Wrap(width int) Frame {
+	if width <= 0 || f.Width <= width {
+		return f
+	}
+	var out Frame
+	for x := 0; x < f.Width; {
+		end := min(x+width, f.Width)
+		if end < f.Width && !f.blankColumn(end) {
+			for e := end - 1; e > x; e-- {
+				if f.blankColumn(e) {
+					end = e + 1
+					break
+				}
+			}
+		}
+		for _, row := range f.Cells {
+			out.Cells = append(out.Cells, row[min(x, len(row)):min(end, len(row))])
+		}
+		out.Width = max(out.Width, end-x)
+		x = end
+	}
+	out.Height = len(out.Cells)
+	return out
+}
+
+// blankColumn reports whether column x is blank in every row.
+func (f Frame) blankColumn(x int) bool {
+	for _, row := range f.Cells {
+		if x < len(row) && !row[x].Blank {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package banner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// Warping onto a curve, skew and perspective
+//------------------------------------------------------------------------------
+
+// Warp bends art along a curve by shifting each column up or down, for
+// badge and logo layouts. The zero value leaves the art straight.
+type Warp struct {
+	Shape  string  // "arc" or "wave"; "" for none
+	Amount float64 // rows the curve rises at its peak; negative bends the other way
+}
+
+// WarpShapes are the curves a Warp can follow.
+var WarpShapes = []string{"arc", "wave"}
+
+// WithWarp bends the composed art along w.
+func WithWarp(w Warp) Option { return func(o *Options) { o.Warp = w } }
+
+// Warped returns a bent along w: an arc, highest in the middle, or a wave
+// with two crests across the width. Letters keep their shape column by
+// column, so slopes are stepped, steeper with a larger Amount.
+func (a Art) Warped(w Warp) (Art, error) {
+	if w.Shape == "" || w.Amount == 0 || a.Width < 2 {
+		return a, nil
+	}
+	offsets := make([]int, a.Width)
+	for x := range offsets {
+		t := float64(x) / float64(a.Width-1) // 0 to 1 across
+		var lift float64
+		switch w.Shape {
+		case "arc":
+			lift = 1 - (2*t-1)*(2*t-1)
+		case "wave":
+			lift = math.Sin(2 * math.Pi * 2 * t)
+		default:
+			return Art{}, fmt.Errorf("unknown warp %q (want arc or wave)", w.Shape)
+		}
+		offsets[x] = -int(math.Round(w.Amount * lift)) // up is fewer rows
+	}
+	low, high := offsets[0], offsets[0]
+	for _, o := range offsets {
+		low, high = min(low, o), max(high, o)
+	}
+
+	out := Art{Rows: make([][]rune, a.height()+high-low), Width: a.Width}
+	for y := range out.Rows {
+		out.Rows[y] = make([]rune, a.Width)
+		for x := range out.Rows[y] {
+			out.Rows[y][x] = ' '
+		}
+	}
+	for y, row := range a.Rows {
+		for x, r := range row {
+			out.Rows[y+offsets[x]-low][x] = r
+		}
+	}
+	return out, nil
+}
+
+// WithSkew leans the composed art k columns right per row up; see Skewed.
+func WithSkew(k float64) Option { return func(o *Options) { o.Skew = k } }
+
+// WithPerspective narrows the composed art towards one edge; see
+// Perspective.
+func WithPerspective(p float64) Option { return func(o *Options) { o.Perspective = p } }
+
+// Skewed returns a sheared sideways, each row k columns right of the one
+// below it, to italicize any font; negative k leans left.
+func (a Art) Skewed(k float64) Art {
+	h := a.height()
+	if k == 0 || h < 2 {
+		return a
+	}
+	shifts := make([]int, h)
+	low, high := 0, 0
+	for y := range shifts {
+		shifts[y] = int(math.Round(k * float64(h-1-y)))
+		low, high = min(low, shifts[y]), max(high, shifts[y])
+	}
+	out := Art{Rows: make([][]rune, h), Width: a.Width + high - low}
+	for y, row := range a.Rows {
+		out.Rows[y] = append([]rune(strings.Repeat(" ", shifts[y]-low)), row...)
+	}
+	out.pad()
+	return out
+}
+
+// MaxPerspective is the most Perspective narrows a row, leaving a tenth
+// of the width.
+const MaxPerspective = 0.9
+
+// Perspective returns a with each row squeezed towards the middle, as if
+// tilted away: p > 0 narrows the top to 1-p of the width, p < 0 the bottom,
+// rows between in proportion. A cell is inked, with the first ink it
+// covers, when at least half the span of the row under it is, so one-cell
+// strokes survive the squeeze without fills running together. p is held
+// to ±MaxPerspective.
+func (a Art) Perspective(p float64) Art {
+	h := a.height()
+	p = min(MaxPerspective, max(-MaxPerspective, p))
+	if p == 0 || h < 2 {
+		return a
+	}
+	out := Art{Rows: make([][]rune, h), Width: a.Width}
+	for y, row := range a.Rows {
+		t := float64(y) / float64(h-1) // 0 at the top
+		if p < 0 {
+			t = 1 - t
+		}
+		scale := 1 - math.Abs(p)*(1-t)
+		n := max(1, int(math.Round(float64(a.Width)*scale)))
+		line := []rune(strings.Repeat(" ", a.Width))
+		left := (a.Width - n) / 2
+		for x := range n {
+			from := int(float64(x) / scale)
+			to := max(int(float64(x+1)/scale), from+1)
+			span := row[min(from, len(row)):min(to, len(row))]
+			ink, first := 0, ' '
+			for _, r := range span {
+				if r != ' ' {
+					ink++
+					if first == ' ' {
+						first = r
+					}
+				}
+			}
+			if 2*ink >= len(span) && ink > 0 {
+				line[left+x] = first
+			}
+		}
+		out.Rows[y] = line
+	}
+	return out
+}
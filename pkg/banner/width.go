@@ -0,0 +1,57 @@
+package banner
+
+import (
+	"sync/atomic"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+//------------------------------------------------------------------------------
+// Cell widths
+//------------------------------------------------------------------------------
+
+var ambiguousWide atomic.Bool
+
+// SetAmbiguousWide says whether East Asian ambiguous-width runes, such as
+// the █ ▓ · fills and box drawing, take two terminal cells instead of one,
+// as they do on many CJK terminals and fonts. It is process-wide and also
+// applies to lipgloss and bubbles width measurements.
+func SetAmbiguousWide(on bool) {
+	ambiguousWide.Store(on)
+	uniseg.EastAsianAmbiguousWidth = 1
+	if on {
+		uniseg.EastAsianAmbiguousWidth = 2
+	}
+	runewidth.DefaultCondition.EastAsianWidth = on
+}
+
+// AmbiguousWide reports the SetAmbiguousWide setting.
+func AmbiguousWide() bool { return ambiguousWide.Load() }
+
+// LocaleAmbiguousWide reports whether the locale (or RUNEWIDTH_EASTASIAN)
+// says ambiguous-width runes are wide; it is the natural default for
+// SetAmbiguousWide.
+func LocaleAmbiguousWide() bool { return runewidth.EastAsianWidth }
+
+// RuneWidth is the number of terminal cells r takes.
+func RuneWidth(r rune) int {
+	if r < 0x80 {
+		return 1
+	}
+	return runewidth.RuneWidth(r)
+}
+
+// cellWidth is how many terminal cells each frame cell takes: 2 if any
+// glyph is wide, so that narrow glyphs and blanks can be padded to match
+// and the art keeps its shape.
+func (f Frame) cellWidth() int {
+	for _, row := range f.Cells {
+		for _, c := range row {
+			if !c.Blank && RuneWidth(c.Rune) > 1 {
+				return 2
+			}
+		}
+	}
+	return 1
+}
@@ -0,0 +1,128 @@
+package banner
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode"
+)
+
+//------------------------------------------------------------------------------
+// Per-word colors
+//------------------------------------------------------------------------------
+
+// WordColor colors every whole-word occurrence of Word in the text, case
+// sensitively, in Color instead of the gradient, as status displays want
+// for words like FAILED.
+type WordColor struct {
+	Word  string
+	Color Color
+}
+
+// Mark is a region of Art colored apart from the gradient.
+type Mark struct {
+	Rect  image.Rectangle // cells covered, in art coordinates
+	Color Color
+}
+
+// colorNames are the names ParseColor knows besides hex.
+var colorNames = map[string]Color{
+	"black": {0, 0, 0}, "red": {255, 85, 85}, "green": {85, 255, 85}, "yellow": {255, 255, 85},
+	"blue": {85, 85, 255}, "magenta": {255, 85, 255}, "cyan": {85, 255, 255}, "white": {255, 255, 255},
+	"gray": {170, 170, 170}, "grey": {170, 170, 170}, "orange": {255, 165, 0}, "pink": {255, 175, 215},
+	"purple": {138, 43, 226},
+}
+
+// ParseColor accepts a hex color, as ParseHex does, or a name such as red
+// or orange.
+func ParseColor(s string) (Color, bool) {
+	if c, ok := ParseHex(s); ok {
+		return c, true
+	}
+	c, ok := colorNames[strings.ToLower(strings.TrimSpace(s))]
+	return c, ok
+}
+
+// ParseWordColors reads rules like "deploy=green, FAILED=red": a word, an
+// equals sign and a color for ParseColor, separated by commas. The empty
+// string is no rules.
+func ParseWordColors(s string) ([]WordColor, error) {
+	var rules []WordColor
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		word, color, ok := strings.Cut(part, "=")
+		word = strings.TrimSpace(word)
+		if !ok || word == "" || strings.ContainsRune(word, ' ') {
+			return nil, fmt.Errorf("word color %q: want WORD=COLOR", part)
+		}
+		c, ok := ParseColor(color)
+		if !ok {
+			return nil, fmt.Errorf("word color %q: unknown color %q", part, strings.TrimSpace(color))
+		}
+		rules = append(rules, WordColor{Word: word, Color: c})
+	}
+	return rules, nil
+}
+
+// FormatWordColors is the inverse of ParseWordColors, colors in hex.
+func FormatWordColors(rules []WordColor) string {
+	parts := make([]string, len(rules))
+	for i, r := range rules {
+		parts[i] = r.Word + "=" + r.Color.Hex()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WithWordColors appends rules for words colored apart from the gradient.
+func WithWordColors(rules ...WordColor) Option {
+	return func(o *Options) { o.Words = append(append([]WordColor(nil), o.Words...), rules...) }
+}
+
+// findWord returns where word occurs in text as a whole word, as rune
+// index ranges: not run into letters or digits either side.
+func findWord(text []rune, word string) [][2]int {
+	w := []rune(word)
+	inWord := func(i int) bool {
+		return i >= 0 && i < len(text) && (unicode.IsLetter(text[i]) || unicode.IsDigit(text[i]))
+	}
+	var found [][2]int
+	for i := 0; i+len(w) <= len(text); i++ {
+		if string(text[i:i+len(w)]) == word && !inWord(i-1) && !inWord(i+len(w)) {
+			found = append(found, [2]int{i, i + len(w)})
+			i += len(w) - 1
+		}
+	}
+	return found
+}
+
+// wordMarks marks the columns of height-row art that each rule's words
+// take. span gives the columns, [from, to), that runes [i, j) of text were
+// drawn in; rules later in the list win where words overlap.
+func wordMarks(text []rune, rules []WordColor, height int, span func(i, j int) (int, int, error)) ([]Mark, error) {
+	var marks []Mark
+	for _, r := range rules {
+		for _, at := range findWord(text, r.Word) {
+			from, to, err := span(at[0], at[1])
+			if err != nil {
+				return nil, err
+			}
+			marks = append(marks, Mark{Rect: image.Rect(from, 0, to, height), Color: r.Color})
+		}
+	}
+	return marks, nil
+}
+
+// markedColor is the color of cell x, y: the last mark covering it, if
+// any, else c.
+func (a Art) markedColor(x, y int, c Color) (Color, bool) {
+	marked := false
+	for _, m := range a.Marks {
+		if image.Pt(x, y).In(m.Rect) {
+			c, marked = m.Color, true
+		}
+	}
+	return c, marked
+}
@@ -0,0 +1,42 @@
+package banner
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseWordColors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []WordColor
+		ok   bool
+	}{
+		{"", nil, true},
+		{"FAILED=red", []WordColor{{"FAILED", colorNames["red"]}}, true},
+		{" deploy = Green , ok=#00ff00 ", []WordColor{{"deploy", colorNames["green"]}, {"ok", Color{G: 255}}}, true},
+		{"a=b=red", nil, false},
+		{"FAILED", nil, false},
+		{"=red", nil, false},
+		{"two words=red", nil, false},
+		{"FAILED=reddish", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseWordColors(tt.in)
+			if (err == nil) != tt.ok || !slices.Equal(got, tt.want) {
+				t.Errorf("ParseWordColors(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWordColors(t *testing.T) {
+	rules := []WordColor{{"deploy", Color{G: 255}}, {"FAILED", colorNames["red"]}}
+	s := FormatWordColors(rules)
+	if want := "deploy=#00ff00, FAILED=#ff5555"; s != want {
+		t.Fatalf("FormatWordColors = %q, want %q", s, want)
+	}
+	if back, err := ParseWordColors(s); err != nil || !slices.Equal(back, rules) {
+		t.Errorf("ParseWordColors(%q) = %v, %v", s, back, err)
+	}
+}
@@ -0,0 +1,403 @@
+// Package colorexpr compiles one-line color formulas into banner effects:
+//
+//	hsv(360*x/w + 40*t, 0.8, 1)
+//
+// The formula is evaluated for every non-blank cell of every frame and must
+// produce a color. Numbers combine with + - * / % ^ and parentheses.
+//
+// Variables: x, y (cell position), w, h (art size), t (ticks since start),
+// r, g, b (the cell's current color, 0–255) and pi.
+//
+// Functions: hsv(h, s, v) and rgb(r, g, b) build colors (hue in degrees,
+// s/v in 0–1, channels in 0–255, all clamped); sin, cos, abs, floor, sqrt,
+// min, max and clamp(v, lo, hi) work on numbers.
+package colorexpr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Effect
+//------------------------------------------------------------------------------
+
+// Effect is a compiled formula. It implements banner.Effect.
+type Effect struct {
+	src   string
+	color colorFn
+	env   env
+}
+
+// env is what a formula can see while it runs.
+type env struct {
+	x, y, w, h, t float64
+	base          banner.Color
+}
+
+type (
+	numFn   func(*env) float64
+	colorFn func(*env) banner.Color
+)
+
+// Parse compiles src. Errors name the offending position.
+func Parse(src string) (*Effect, error) {
+	p := &parser{src: src}
+	p.next()
+	n, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	c, err := n.color()
+	if err != nil {
+		return nil, err
+	}
+	return &Effect{src: src, color: c}, nil
+}
+
+// String returns the formula as written.
+func (e *Effect) String() string { return e.src }
+
+func (e *Effect) Init(width, height int) {
+	e.env.w, e.env.h = float64(width), float64(height)
+}
+
+func (e *Effect) Tick() { e.env.t++ }
+
+func (e *Effect) Apply(f banner.Frame) banner.Frame {
+	for y, row := range f.Cells {
+		for x := range row {
+			if row[x].Blank {
+				continue
+			}
+			e.env.x, e.env.y, e.env.base = float64(x), float64(y), row[x].FG
+			row[x].FG = e.color(&e.env)
+		}
+	}
+	return f
+}
+
+//------------------------------------------------------------------------------
+// Syntax tree
+//------------------------------------------------------------------------------
+
+// node is a parsed expression. Formulas are type-checked when compiled, so a
+// compiled Effect cannot fail while rendering.
+type node struct {
+	pos  int
+	op   string // number literal, variable or function name, or operator
+	num  float64
+	args []*node
+}
+
+var variables = map[string]numFn{
+	"x":  func(e *env) float64 { return e.x },
+	"y":  func(e *env) float64 { return e.y },
+	"w":  func(e *env) float64 { return e.w },
+	"h":  func(e *env) float64 { return e.h },
+	"t":  func(e *env) float64 { return e.t },
+	"r":  func(e *env) float64 { return float64(e.base.R) },
+	"g":  func(e *env) float64 { return float64(e.base.G) },
+	"b":  func(e *env) float64 { return float64(e.base.B) },
+	"pi": func(*env) float64 { return math.Pi },
+}
+
+var numFuncs = map[string]struct {
+	arity int
+	fn    func(a []float64) float64
+}{
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"clamp": {3, func(a []float64) float64 { return clamp(a[0], a[1], a[2]) }},
+}
+
+var binary = map[string]func(a, b float64) float64{
+	"+": func(a, b float64) float64 { return a + b },
+	"-": func(a, b float64) float64 { return a - b },
+	"*": func(a, b float64) float64 { return a * b },
+	"/": func(a, b float64) float64 { return a / b },
+	"%": math.Mod,
+	"^": math.Pow,
+}
+
+func (n *node) errorf(format string, args ...any) error {
+	return fmt.Errorf("col %d: %s", n.pos+1, fmt.Sprintf(format, args...))
+}
+
+// color compiles n as a color-valued expression.
+func (n *node) color() (colorFn, error) {
+	if n.op != "hsv" && n.op != "rgb" {
+		return nil, n.errorf("formula must produce a color, e.g. hsv(...) or rgb(...)")
+	}
+	if len(n.args) != 3 {
+		return nil, n.errorf("%s takes 3 arguments", n.op)
+	}
+	a, err := n.numArgs()
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "hsv" {
+		return func(e *env) banner.Color {
+			return banner.HSV(hue(a[0](e)), clamp(a[1](e), 0, 1), clamp(a[2](e), 0, 1))
+		}, nil
+	}
+	return func(e *env) banner.Color {
+		return banner.Color{R: channel(a[0](e)), G: channel(a[1](e)), B: channel(a[2](e))}
+	}, nil
+}
+
+// number compiles n as a number-valued expression.
+func (n *node) number() (numFn, error) {
+	if n.args == nil {
+		if n.op == "" {
+			v := n.num
+			return func(*env) float64 { return v }, nil
+		}
+		if fn, ok := variables[n.op]; ok {
+			return fn, nil
+		}
+	}
+	if op, ok := binary[n.op]; ok && len(n.args) == 2 {
+		a, err := n.numArgs()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *env) float64 { return op(a[0](e), a[1](e)) }, nil
+	}
+	if n.op == "neg" {
+		a, err := n.numArgs()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *env) float64 { return -a[0](e) }, nil
+	}
+	if f, ok := numFuncs[n.op]; ok {
+		if len(n.args) != f.arity {
+			return nil, n.errorf("%s takes %d argument(s)", n.op, f.arity)
+		}
+		a, err := n.numArgs()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *env) float64 {
+			vals := make([]float64, len(a))
+			for i, fn := range a {
+				vals[i] = fn(e)
+			}
+			return f.fn(vals)
+		}, nil
+	}
+	if n.op == "hsv" || n.op == "rgb" {
+		return nil, n.errorf("%s gives a color where a number is needed", n.op)
+	}
+	return nil, n.errorf("unknown name %q", n.op)
+}
+
+func (n *node) numArgs() ([]numFn, error) {
+	out := make([]numFn, len(n.args))
+	for i, a := range n.args {
+		fn, err := a.number()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = fn
+	}
+	return out, nil
+}
+
+// clamp limits v to [lo, hi]; NaN becomes lo.
+func clamp(v, lo, hi float64) float64 {
+	if math.IsNaN(v) || v < lo {
+		return lo
+	}
+	return math.Min(v, hi)
+}
+
+func channel(v float64) int { return int(clamp(v, 0, 255)) }
+
+// hue brings v into [0, 360), with NaN and infinities, as from 1/0 or 0%0,
+// taken as 0.
+func hue(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	if v = math.Mod(v, 360); v < 0 {
+		v += 360
+	}
+	return v
+}
+
+//------------------------------------------------------------------------------
+// Parser
+//------------------------------------------------------------------------------
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNum
+	tokName
+	tokOp // operators, parentheses and commas
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// parser is a precedence-climbing parser over a one-token lookahead.
+type parser struct {
+	src string
+	off int
+	tok token
+	err error // first lexing error
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("col %d: %s", p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) next() {
+	for p.off < len(p.src) && p.src[p.off] == ' ' {
+		p.off++
+	}
+	start := p.off
+	if p.off >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := rune(p.src[p.off])
+	switch {
+	case unicode.IsDigit(c) || c == '.':
+		for p.off < len(p.src) && (unicode.IsDigit(rune(p.src[p.off])) || p.src[p.off] == '.') {
+			p.off++
+		}
+		p.tok = token{tokNum, p.src[start:p.off], start}
+	case unicode.IsLetter(c) || c == '_':
+		for p.off < len(p.src) && (unicode.IsLetter(rune(p.src[p.off])) || unicode.IsDigit(rune(p.src[p.off])) || p.src[p.off] == '_') {
+			p.off++
+		}
+		p.tok = token{tokName, p.src[start:p.off], start}
+	case strings.ContainsRune("+-*/%^(),", c):
+		p.off++
+		p.tok = token{tokOp, string(c), start}
+	default:
+		p.off++
+		p.tok = token{tokOp, string(c), start}
+		if p.err == nil {
+			p.err = p.errorf("unexpected %q", string(c))
+		}
+	}
+}
+
+var precedence = map[string]int{"+": 1, "-": 1, "*": 2, "/": 2, "%": 2, "^": 3}
+
+// expr parses operators binding tighter than minPrec. ^ is right
+// associative; the rest are left associative.
+func (p *parser) expr(minPrec int) (*node, error) {
+	lhs, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp {
+		op := p.tok.text
+		prec, ok := precedence[op]
+		if !ok || prec <= minPrec {
+			break
+		}
+		pos := p.tok.pos
+		p.next()
+		next := prec
+		if op == "^" {
+			next = prec - 1
+		}
+		rhs, err := p.expr(next)
+		if err != nil {
+			return nil, err
+		}
+		lhs = &node{pos: pos, op: op, args: []*node{lhs, rhs}}
+	}
+	return lhs, p.err
+}
+
+func (p *parser) unary() (*node, error) {
+	if p.tok.kind == tokOp && p.tok.text == "-" {
+		pos := p.tok.pos
+		p.next()
+		// Unary minus binds looser than ^, so -x^2 is -(x^2).
+		n, err := p.expr(precedence["*"])
+		if err != nil {
+			return nil, err
+		}
+		return &node{pos: pos, op: "neg", args: []*node{n}}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (*node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokNum:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("bad number %q", tok.text)
+		}
+		p.next()
+		return &node{pos: tok.pos, num: v}, nil
+	case tokName:
+		p.next()
+		n := &node{pos: tok.pos, op: tok.text}
+		if p.tok.kind != tokOp || p.tok.text != "(" {
+			return n, nil
+		}
+		p.next()
+		n.args = []*node{}
+		for p.tok.kind != tokOp || p.tok.text != ")" {
+			arg, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, arg)
+			if p.tok.kind == tokOp && p.tok.text == "," {
+				p.next()
+			} else if p.tok.kind != tokOp || p.tok.text != ")" {
+				return nil, p.errorf("expected , or ) in call to %s", tok.text)
+			}
+		}
+		p.next()
+		return n, nil
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			n, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokOp || p.tok.text != ")" {
+				return nil, p.errorf("expected )")
+			}
+			p.next()
+			return n, nil
+		}
+	case tokEOF:
+		return nil, p.errorf("unexpected end of formula")
+	}
+	return nil, p.errorf("unexpected %q", tok.text)
+}
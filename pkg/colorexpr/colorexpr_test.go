@@ -0,0 +1,93 @@
+package colorexpr
+
+import (
+	"testing"
+
+	"glamdm/pkg/banner"
+)
+
+func TestHSVHueOutOfRange(t *testing.T) {
+	for _, src := range []string{"hsv(1/0, 1, 1)", "hsv(0%0, 1, 1)", "hsv(-1/0, 1, 1)", "hsv(-30, 1, 1)", "hsv(720, 1, 1)"} {
+		t.Run(src, func(t *testing.T) {
+			e, err := Parse(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := banner.Render("Hi", banner.WithEffect(e))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range f.Cells {
+				for _, c := range row {
+					if !c.Blank && (c.FG.R < 0 || c.FG.R > 255 || c.FG.G < 0 || c.FG.G > 255 || c.FG.B < 0 || c.FG.B > 255) {
+						t.Fatalf("color %v out of range", c.FG)
+					}
+				}
+			}
+			_ = f.ANSI() // used to dereference a nil color
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want banner.Color // of the cell at x=2, y=1 in a 4x2 art, base #102030
+	}{
+		{"rgb(255, 0, 0)", banner.Color{R: 255}},
+		{"rgb(r, g, b)", banner.Color{R: 16, G: 32, B: 48}},
+		{"rgb(x * 100, y * 100, w * h)", banner.Color{R: 200, G: 100, B: 8}},
+		{"rgb(1 + 2 * 3, (1 + 2) * 3, 2 ^ 3)", banner.Color{R: 7, G: 9, B: 8}},
+		{"rgb(-x * -10, 7 % 4, 9 / 2)", banner.Color{R: 20, G: 3, B: 4}},
+		{"rgb(300, -5, 128)", banner.Color{R: 255, B: 128}},
+		{"rgb(min(x, y), max(x, y), clamp(400, 0, 10))", banner.Color{R: 1, G: 2, B: 10}},
+		{"rgb(abs(-3), floor(2.7), sqrt(16))", banner.Color{R: 3, G: 2, B: 4}},
+		{"hsv(0, 1, 1)", banner.Color{R: 255}},
+		{"hsv(120, 1, 1)", banner.Color{G: 255}},
+		{"hsv(360 + 240, 1, 1)", banner.Color{B: 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.String() != tt.src {
+				t.Errorf("String() = %q", e.String())
+			}
+			cells := make([][]banner.Cell, 2)
+			for y := range cells {
+				cells[y] = make([]banner.Cell, 4)
+				for x := range cells[y] {
+					cells[y][x] = banner.Cell{Rune: '#', FG: banner.Color{R: 16, G: 32, B: 48}}
+				}
+			}
+			e.Init(4, 2)
+			f := e.Apply(banner.Frame{Width: 4, Height: 2, Cells: cells})
+			if got := f.Cells[1][2].FG; got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"rgb(1, 2)",
+		"rgb(1, 2, 3",
+		"rgb(1 2 3)",
+		"hsv(0, 1, 1) + 1",
+		"x + 1",
+		"nope(1, 2, 3)",
+		"rgb(q, 0, 0)",
+		"rgb(1, 2, 3) )",
+		"rgb(1, 2, $)",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := Parse(src); err == nil {
+				t.Errorf("Parse(%q) succeeded", src)
+			}
+		})
+	}
+}
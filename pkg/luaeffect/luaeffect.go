@@ -0,0 +1,210 @@
+// Package luaeffect runs user Lua scripts as banner effects. A script
+// defines
+//
+//	function cell(x, y, t, color, glyph)
+//	  return hsv((x * 8 + t * 4) % 360, 0.8, 1), glyph
+//	end
+//
+// which is called for every non-blank cell of each frame. x and y are the
+// cell position, t counts ticks, color is the cell's "#rrggbb" and glyph its
+// character. It returns a new color and, optionally, a new glyph; returning
+// nil keeps the color. The globals width and height hold the art size, and
+// hsv(h, s, v), rgb(r, g, b) and unhex(color) help with color math.
+//
+// Scripts run in a sandbox with only the base, string, math and table
+// libraries, and print writes to the log set with SetLog, if any. A frame
+// that takes longer than FrameTimeout stops the script.
+package luaeffect
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"glamdm/pkg/banner"
+)
+
+// FrameTimeout bounds how long a script may take over one frame, or over
+// running its top level when loaded, so a runaway loop can't hang the
+// viewer.
+const FrameTimeout = 250 * time.Millisecond
+
+// Effect is a banner.Effect backed by a Lua script. It must be used from
+// one goroutine at a time.
+type Effect struct {
+	name string
+	L    *lua.LState
+	fn   lua.LValue
+	t    int
+	err  error // first runtime error; the effect stops applying after it
+	log  *log.Logger
+}
+
+// Load compiles the script at path.
+func Load(path string) (*Effect, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(path, string(src))
+}
+
+// New compiles src; name is used in error messages.
+func New(name, src string) (*Effect, error) {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.TabLibName, lua.OpenTable},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// No loading other code from inside the sandbox.
+	for _, unsafe := range []string{"dofile", "loadfile", "load", "loadstring", "require"} {
+		L.SetGlobal(unsafe, lua.LNil)
+	}
+	L.SetGlobal("hsv", L.NewFunction(luaHSV))
+	L.SetGlobal("rgb", L.NewFunction(luaRGB))
+	L.SetGlobal("unhex", L.NewFunction(luaUnhex))
+	e := &Effect{name: name, L: L}
+	L.SetGlobal("print", L.NewFunction(e.print))
+
+	stop := e.deadline()
+	err := L.DoString(src)
+	if err = stop(err); err != nil {
+		L.Close()
+		return nil, err
+	}
+	e.fn = L.GetGlobal("cell")
+	if e.fn.Type() != lua.LTFunction {
+		L.Close()
+		return nil, fmt.Errorf("%s: script must define function cell(x, y, t, color, glyph)", name)
+	}
+	return e, nil
+}
+
+// SetLog sends the script's print output to l; nil, the default, discards
+// it.
+func (e *Effect) SetLog(l *log.Logger) { e.log = l }
+
+func (e *Effect) print(L *lua.LState) int {
+	if e.log == nil {
+		return 0
+	}
+	args := make([]string, L.GetTop())
+	for i := range args {
+		args[i] = L.ToStringMeta(L.Get(i + 1)).String()
+	}
+	e.log.Printf("%s: %s", e.name, strings.Join(args, "\t"))
+	return 0
+}
+
+// deadline gives the script FrameTimeout from now. The returned function
+// ends it and turns err, the outcome of the run, into the error to report.
+func (e *Effect) deadline() func(err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), FrameTimeout)
+	e.L.SetContext(ctx)
+	return func(err error) error {
+		e.L.RemoveContext()
+		cancel()
+		switch {
+		case err == nil:
+			return nil
+		case ctx.Err() != nil:
+			return fmt.Errorf("%s: took longer than %v", e.name, FrameTimeout)
+		}
+		return fmt.Errorf("%s: %s", e.name, message(err))
+	}
+}
+
+// Close releases the Lua state.
+func (e *Effect) Close() { e.L.Close() }
+
+// Err reports the runtime error that disabled the script, if any.
+func (e *Effect) Err() error { return e.err }
+
+func (e *Effect) Init(width, height int) {
+	e.L.SetGlobal("width", lua.LNumber(width))
+	e.L.SetGlobal("height", lua.LNumber(height))
+}
+
+func (e *Effect) Tick() { e.t++ }
+
+func (e *Effect) Apply(f banner.Frame) banner.Frame {
+	if e.err != nil {
+		return f
+	}
+	stop := e.deadline()
+	defer func() { e.err = stop(e.err) }()
+	for y, row := range f.Cells {
+		for x := range row {
+			c := &row[x]
+			if c.Blank {
+				continue
+			}
+			err := e.L.CallByParam(lua.P{Fn: e.fn, NRet: 2, Protect: true},
+				lua.LNumber(x), lua.LNumber(y), lua.LNumber(e.t),
+				lua.LString(c.FG.Hex()), lua.LString(string(c.Rune)))
+			if err != nil {
+				e.err = err
+				return f
+			}
+			glyph, color := e.L.Get(-1), e.L.Get(-2)
+			e.L.Pop(2)
+			if s, ok := color.(lua.LString); ok {
+				if col, ok := banner.ParseHex(string(s)); ok {
+					c.FG = col
+				}
+			}
+			if s, ok := glyph.(lua.LString); ok {
+				if r := []rune(string(s)); len(r) > 0 {
+					c.Rune = r[0]
+				}
+			}
+		}
+	}
+	return f
+}
+
+func luaHSV(L *lua.LState) int {
+	c := banner.HSV(float64(L.CheckNumber(1)), float64(L.CheckNumber(2)), float64(L.CheckNumber(3)))
+	L.Push(lua.LString(c.Hex()))
+	return 1
+}
+
+func luaRGB(L *lua.LState) int {
+	clamp := func(n lua.LNumber) int { return max(0, min(255, int(n))) }
+	c := banner.Color{R: clamp(L.CheckNumber(1)), G: clamp(L.CheckNumber(2)), B: clamp(L.CheckNumber(3))}
+	L.Push(lua.LString(c.Hex()))
+	return 1
+}
+
+func luaUnhex(L *lua.LState) int {
+	c, ok := banner.ParseHex(L.CheckString(1))
+	if !ok {
+		L.ArgError(1, "not a hex color")
+	}
+	L.Push(lua.LNumber(c.R))
+	L.Push(lua.LNumber(c.G))
+	L.Push(lua.LNumber(c.B))
+	return 3
+}
+
+// message drops the Lua stack trace, which is noise in a status line.
+func message(err error) string {
+	if apiErr, ok := err.(*lua.ApiError); ok && apiErr.Object != nil {
+		return apiErr.Object.String()
+	}
+	return err.Error()
+}
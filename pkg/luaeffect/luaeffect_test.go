@@ -0,0 +1,47 @@
+package luaeffect
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"glamdm/pkg/banner"
+)
+
+func frame() banner.Frame {
+	return banner.Frame{Cells: [][]banner.Cell{{{Rune: 'A', FG: banner.Color{R: 255}}}}}
+}
+
+func TestApplyTimeout(t *testing.T) {
+	e, err := New("spin", "function cell() while true do end end")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	e.Apply(frame())
+	if e.Err() == nil || !strings.Contains(e.Err().Error(), "took longer") {
+		t.Fatalf("Err() = %v, want a timeout", e.Err())
+	}
+}
+
+func TestLoadTimeout(t *testing.T) {
+	if _, err := New("spin", "while true do end"); err == nil || !strings.Contains(err.Error(), "took longer") {
+		t.Fatalf("New = %v, want a timeout", err)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	e, err := New("hi", `function cell(x, y, t, c, g) print("cell", x, g) return c end`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	e.Apply(frame()) // no log: discarded
+	var buf bytes.Buffer
+	e.SetLog(log.New(&buf, "", 0))
+	e.Apply(frame())
+	if got, want := buf.String(), "hi: cell\t0\tA\n"; got != want || e.Err() != nil {
+		t.Fatalf("log %q, err %v; want %q", got, e.Err(), want)
+	}
+}
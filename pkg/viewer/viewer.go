@@ -0,0 +1,1122 @@
+// Package viewer is the banner editor/preview as an embeddable Bubble Tea
+// component. Host programs route messages to it, position its View, and
+// listen for SelectedMsg:
+//
+//	v := viewer.New()
+//	v.SetText("deploy")
+//	...
+//	case viewer.SelectedMsg:
+//		banner := msg.Frame.String()
+//
+// The host owns quitting; the viewer never returns tea.Quit.
+package viewer
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Messages
+//------------------------------------------------------------------------------
+
+// SelectedMsg is sent when the user presses enter to pick the current banner.
+type SelectedMsg struct {
+	Text  string
+	Font  string
+	Frame banner.Frame
+}
+
+// TickMsg advances the hue animation of the viewer with the matching ID.
+type TickMsg struct {
+	ID  int
+	tag int
+}
+
+// RebuildMsg recomposes the art of the viewer with the matching ID once
+// typing pauses.
+type RebuildMsg struct {
+	ID  int
+	seq int
+}
+
+// PrerenderMsg starts composing the fonts around the current one for the
+// viewer with the matching ID, once the user has been idle a moment.
+type PrerenderMsg struct {
+	ID  int
+	seq int
+}
+
+// PrerenderedMsg delivers art composed in the background.
+type PrerenderedMsg struct {
+	ID  int
+	seq int
+	key artKey
+	art banner.Art
+}
+
+// maxCells caps the estimated size of the art. A pasted paragraph in a big
+// font would otherwise take seconds to compose and fill every frame.
+const maxCells = 40000
+
+// fallbackFont shows the text when the selected font fails to load.
+const fallbackFont = "standard"
+
+// typingDebounce is how long typing must pause before the art is
+// recomposed; big fonts make every composition slow.
+const typingDebounce = 75 * time.Millisecond
+
+// prerenderIdle is how long the viewer must sit idle before the
+// neighbouring fonts are composed; prerenderSpan is how many fonts on each
+// side of the current one that covers.
+const (
+	prerenderIdle = 300 * time.Millisecond
+	prerenderSpan = 2
+)
+
+// Big banners slow the animation down rather than the UI: past cellBudget
+// cell-effect applications per tick, or when drawing a frame takes more
+// than half the interval, ticks are spaced out, up to maxInterval.
+const (
+	cellBudget  = 20000
+	maxInterval = 250 * time.Millisecond
+)
+
+var lastID int64
+
+func nextID() int { return int(atomic.AddInt64(&lastID, 1)) }
+
+//------------------------------------------------------------------------------
+// Model
+//------------------------------------------------------------------------------
+
+// Model is the banner editor: text and color inputs, font and mode cycling,
+// and an animated gradient preview.
+type Model struct {
+	id        int
+	tag       int // invalidates ticks from before the last animation restart
+	typingSeq int // invalidates RebuildMsgs from before the last keystroke
+	idleSeq   int // invalidates PrerenderMsgs from before the last keystroke
+	artSeq    int // invalidates PrerenderedMsgs composed with old normalization or word colors
+
+	// Controls
+	inputs     []textinput.Model // 0=text, 1=start hex, 2=end hex
+	keys       KeyMap
+	channels   []textinput.Model // start R, G, B, end R, G, B; in place of the hex inputs while rgb
+	rgb        bool              // colors are entered as numbers
+	focusIndex int               // into fields()
+	sanitized  bool              // control characters were removed from the text
+
+	fonts     []string
+	fontIndex int
+	fontOrder string // one of FontOrders
+
+	norm  banner.Normalization // applied to the text before composing
+	width int                  // columns the preview may take; 0 for no limit
+	words []banner.WordColor   // words colored apart from the gradient
+
+	// Render cache
+	art     banner.Art
+	err     error      // last composition error
+	fontErr error      // the font failed; art is in fallbackFont
+	built   artKey     // inputs art and err were composed from
+	draw    *drawState // preview storage reused between frames
+	ticks   int        // effect ticks so far
+	gen     int        // bumped when the preview changes outside drawKey
+
+	composed map[artKey]banner.Art // current text by font, incl. pre-rendered
+	idleFor  artKey                // built when pre-rendering was last scheduled
+	shown    *banner.Art           // shown instead of the text, if set
+	cursor   [2]int                // cell marked in the preview, if cursorOn
+	cursorOn bool                  // mark the cursor cell
+	sequence []banner.Art          // hand-made animation played instead of the text
+	every    time.Duration         // how long each sequence frame shows
+	played   time.Duration         // animation time into the sequence
+
+	// Colors (base are user-chosen; effective may be hue-rotated)
+	baseStart banner.Color
+	baseEnd   banner.Color
+	stops     []banner.Stop // between the endpoints
+
+	// Mode
+	mode banner.RenderMode
+
+	// Animation: effects tick and apply only while animating
+	animate  bool
+	effects  []banner.Effect
+	hue      *banner.HueCycle // always in effects
+	interval time.Duration    // tick interval
+
+	renderer *lipgloss.Renderer // output the views are styled for
+	debug    *log.Logger        // compose and draw timings; nil for none
+}
+
+// drawState is the preview's storage, shared by a Model's copies so the
+// value-receiver views can keep it between frames.
+type drawState struct {
+	frame banner.Frame  // reused between frames
+	took  time.Duration // how long the last fresh preview took to draw
+	key   drawKey       // inputs out was drawn from
+	out   string        // last preview
+}
+
+// drawKey is everything the preview depends on; while it stays the same,
+// the last preview is reused.
+type drawKey struct {
+	art        artKey
+	start, end banner.Color
+	mode       string // by name: modes need not be comparable
+	animate    bool
+	ticks, gen int
+	renderer   *lipgloss.Renderer
+	cursor     [2]int
+	cursorOn   bool
+	width      int
+}
+
+// artKey identifies composed art. Fonts are never "", so the zero key
+// never matches.
+type artKey struct{ text, font string }
+
+func newTextInput(placeholder string, value string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.SetValue(value)
+	ti.Prompt = ""
+	ti.CharLimit = 256
+	ti.Width = max(12, utf8.RuneCountInString(value)+4)
+	return ti
+}
+
+// New returns a viewer with the default look.
+func New() Model {
+	m := Model{
+		id:        nextID(),
+		fonts:     banner.FontNames(),
+		fontOrder: FontOrders[0],
+		keys:      DefaultKeyMap(),
+		baseStart: banner.Color{R: 138, G: 43, B: 226}, // #8A2BE2
+		baseEnd:   banner.Color{R: 0, G: 255, B: 255},  // #00FFFF
+		mode:      banner.ModeGlyph,                    // default: keep original glyphs
+		animate:   true,
+		hue:       banner.NewHueCycle(3), // degrees per tick
+		interval:  60 * time.Millisecond, // ~16 FPS
+		renderer:  lipgloss.DefaultRenderer(),
+		draw:      &drawState{},
+		composed:  map[artKey]banner.Art{},
+	}
+	m.effects = []banner.Effect{m.hue}
+	m.inputs = []textinput.Model{
+		newTextInput("text", "glam dm"),
+		newTextInput("start hex", "#8A2BE2"),
+		newTextInput("end hex", "#00FFFF"),
+	}
+	m.inputs[0].Focus()
+	m.rebuildArt()
+	return m
+}
+
+// compose renders text in font, cut short by fitText, marking words.
+func compose(text, font string, n banner.Normalization, words []banner.WordColor) (banner.Art, error) {
+	text, _ = fitText(text, font)
+	return banner.Compose(text, banner.WithFont(font), banner.WithNormalization(n), banner.WithWordColors(words...))
+}
+
+// fitText cuts text that would make art of more than maxCells in font down
+// to size, ending it with "...". It returns the text and how many of its
+// characters were kept, or -1 if it fits as is.
+func fitText(text, font string) (string, int) {
+	w, h, err := banner.GlyphSize(font)
+	if err != nil || w*h == 0 {
+		return text, -1
+	}
+	rs := []rune(text)
+	n := maxCells / (w * h)
+	if len(rs) <= n {
+		return text, -1
+	}
+	keep := max(0, n-3)
+	return string(rs[:keep]) + "...", keep
+}
+
+// rebuildArt composes the art if the text or font changed since last time,
+// taking it from the pre-rendered fonts when it is there. A font that fails
+// is reported and the art drawn in fallbackFont instead.
+func (m *Model) rebuildArt() {
+	if m.shown != nil {
+		m.err, m.fontErr = nil, nil
+		m.setArt(*m.shown)
+		return
+	}
+	if len(m.sequence) > 0 {
+		m.err, m.fontErr = nil, nil
+		m.setArt(m.sequence[int(m.played/m.every)%len(m.sequence)])
+		return
+	}
+	key := artKey{m.inputs[0].Value(), m.fonts[m.fontIndex]}
+	if key == m.built {
+		return
+	}
+	if key.text != m.built.text {
+		clear(m.composed)
+	}
+	m.built = key
+	m.fontErr = nil
+	art, ok := m.composed[key]
+	if ok {
+		m.debugf("compose %q in %s: pre-rendered", key.text, key.font)
+	} else {
+		var err error
+		start := time.Now()
+		art, err = compose(key.text, key.font, m.norm, m.words)
+		m.debugf("compose %q in %s: %v", key.text, key.font, time.Since(start))
+		if err != nil && key.font != fallbackFont {
+			m.debugf("font %s: %v", key.font, err)
+			if fb, fbErr := compose(key.text, fallbackFont, m.norm, m.words); fbErr == nil {
+				art, m.fontErr, err = fb, err, nil
+			}
+		}
+		m.err = err
+		if err != nil {
+			m.debugf("compose: %v", err)
+			return
+		}
+		if m.fontErr == nil {
+			m.composed[key] = art
+		}
+	}
+	m.err = nil
+	m.setArt(art)
+}
+
+// setArt makes art the one drawn, resetting the effects if its size
+// changed.
+func (m *Model) setArt(art banner.Art) {
+	resized := art.Width != m.art.Width || len(art.Rows) != len(m.art.Rows)
+	m.art = art
+	if resized {
+		for _, e := range m.effects {
+			e.Init(art.Width, len(art.Rows))
+		}
+	}
+}
+
+// ShowArt shows art, such as a picture from banner.ImageArt or an edited
+// canvas, in place of the composed text; nil goes back to the text.
+func (m *Model) ShowArt(art *banner.Art) {
+	m.shown = art
+	m.built = artKey{}
+	m.gen++
+	m.rebuildArt()
+}
+
+// SetSequence plays frames, such as those of banner.LoadSequence, in place
+// of the text while animating, each shown for every; nil goes back to the
+// text.
+func (m *Model) SetSequence(frames []banner.Art, every time.Duration) {
+	m.sequence, m.every, m.played = frames, max(every, time.Millisecond), 0
+	m.built = artKey{}
+	m.gen++
+	m.rebuildArt()
+}
+
+// Art returns the art as composed, before coloring.
+func (m Model) Art() banner.Art { return m.art }
+
+// SetCursor marks the cell at column x, row y of the art in the preview.
+func (m *Model) SetCursor(x, y int) { m.cursor, m.cursorOn = [2]int{x, y}, true }
+
+// HideCursor stops marking a cell.
+func (m *Model) HideCursor() { m.cursorOn = false }
+
+//------------------------------------------------------------------------------
+// Getters & setters
+//------------------------------------------------------------------------------
+
+// ID identifies this viewer's tick messages.
+func (m Model) ID() int { return m.id }
+
+func (m Model) Text() string { return m.inputs[0].Value() }
+
+// SetText replaces the text, sanitized as typed text is.
+func (m *Model) SetText(s string) {
+	s, m.sanitized = banner.Sanitize(s)
+	m.inputs[0].SetValue(s)
+	m.rebuildArt()
+}
+
+// Sanitized reports whether control characters or escape sequences were
+// removed from the text since it was last edited cleanly.
+func (m Model) Sanitized() bool { return m.sanitized }
+
+func (m Model) Font() string { return m.fonts[m.fontIndex] }
+
+// Fonts lists the fonts the viewer cycles through.
+func (m Model) Fonts() []string { return append([]string(nil), m.fonts...) }
+
+// SetFont selects a font by name.
+func (m *Model) SetFont(name string) error {
+	for i, f := range m.fonts {
+		if f == name {
+			m.fontIndex = i
+			m.rebuildArt()
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown font %q", name)
+}
+
+// FontOrders are the ways SetFontOrder can sort the font list: as shipped,
+// by name, or by the height or width of the current text in each font.
+var FontOrders = []string{"default", "name", "height", "width"}
+
+// FontOrder is how the font list is sorted.
+func (m Model) FontOrder() string { return m.fontOrder }
+
+// SetFontOrder sorts the font list, keeping the current font. Sorting by
+// size composes the current text in every font, so only the text at the
+// time counts; the compositions are kept for cycling. Fonts that can't
+// draw the text go last.
+func (m *Model) SetFontOrder(order string) error {
+	if !slices.Contains(FontOrders, order) {
+		return fmt.Errorf("unknown font order %q (want %s)", order, strings.Join(FontOrders, ", "))
+	}
+	current := m.Font()
+	fonts := banner.FontNames()
+	switch order {
+	case "name":
+		slices.Sort(fonts)
+	case "height", "width":
+		text := m.inputs[0].Value()
+		size := map[string]int{}
+		for _, f := range fonts {
+			key := artKey{text, f}
+			art, ok := m.composed[key]
+			if !ok {
+				var err error
+				if art, err = compose(text, f, m.norm, m.words); err != nil {
+					size[f] = math.MaxInt
+					continue
+				}
+				m.composed[key] = art
+			}
+			size[f] = art.Width
+			if order == "height" {
+				size[f] = len(art.Rows)
+			}
+		}
+		slices.SortStableFunc(fonts, func(a, b string) int { return cmp.Or(cmp.Compare(size[a], size[b]), cmp.Compare(a, b)) })
+	}
+	m.fonts, m.fontOrder = fonts, order
+	m.fontIndex = max(0, slices.Index(fonts, current))
+	m.rebuildArt()
+	return nil
+}
+
+// SetWidth limits the preview to w columns, 0 for no limit; wider banners
+// wrap. A tea.WindowSizeMsg sets it to the terminal width.
+func (m *Model) SetWidth(w int) { m.width = max(0, w) }
+
+// SkipFonts moves n fonts along the list, back if n is negative, wrapping
+// round at either end.
+func (m *Model) SkipFonts(n int) {
+	l := len(m.fonts)
+	m.fontIndex = ((m.fontIndex+n)%l + l) % l
+	m.rebuildArt()
+}
+
+// Colors returns the gradient endpoints as typed in the inputs.
+func (m Model) Colors() (start, end string) { return m.inputs[1].Value(), m.inputs[2].Value() }
+
+// SetColors sets the gradient endpoints from hex strings.
+func (m *Model) SetColors(start, end string) error {
+	s, ok := banner.ParseHex(start)
+	if !ok {
+		return fmt.Errorf("invalid color %q", start)
+	}
+	e, ok := banner.ParseHex(end)
+	if !ok {
+		return fmt.Errorf("invalid color %q", end)
+	}
+	m.inputs[1].SetValue(start)
+	m.inputs[2].SetValue(end)
+	m.baseStart, m.baseEnd = s, e
+	if m.rgb {
+		m.hexToChannels()
+	}
+	return nil
+}
+
+// Stops are the gradient's colors between its endpoints, in position order.
+func (m Model) Stops() []banner.Stop { return m.stops }
+
+// SetStops replaces the colors between the endpoints.
+func (m *Model) SetStops(stops []banner.Stop) {
+	m.stops = slices.Clone(stops)
+	banner.SortStops(m.stops)
+	m.gen++
+}
+
+//------------------------------------------------------------------------------
+// Keys
+//------------------------------------------------------------------------------
+
+// KeyMap is the viewer's key bindings. Their help text makes the hints
+// under the preview.
+type KeyMap struct {
+	Select, NextField, PrevField  key.Binding
+	PrevFont, NextFont            key.Binding
+	Mode, Animate, Faster, Slower key.Binding
+	RGB, Channel                  key.Binding
+}
+
+// DefaultKeyMap is the bindings New starts with.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		NextField: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
+		PrevField: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous field")),
+		PrevFont:  key.NewBinding(key.WithKeys("left", "["), key.WithHelp("←/→", "font")), // one hint for both
+		NextFont:  key.NewBinding(key.WithKeys("right", "]")),
+		Mode:      key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mode")),
+		Animate:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "hue cycle")),
+		Faster:    key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+/-", "speed")),
+		Slower:    key.NewBinding(key.WithKeys("-", "_")),
+		RGB:       key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "hex/rgb")),
+		Channel:   key.NewBinding(key.WithKeys("up", "down", "shift+up", "shift+down"), key.WithHelp("↑/↓", "±1 (shift ±16)")),
+	}
+}
+
+// Keys returns the key bindings.
+func (m Model) Keys() KeyMap { return m.keys }
+
+// SetKeys replaces the key bindings.
+func (m *Model) SetKeys(k KeyMap) { m.keys = k }
+
+// KeyHints are the bindings that matter for the field with the focus:
+// fonts and modes while on the text, color entry on a color.
+func (m Model) KeyHints() []key.Binding {
+	k := m.keys
+	if m.focusIndex == 0 {
+		return []key.Binding{k.Select, k.NextField, k.PrevFont, k.NextFont, k.Mode, k.Animate, k.Faster, k.Slower}
+	}
+	if m.rgb {
+		return []key.Binding{k.NextField, k.PrevField, k.Channel, k.RGB, k.Animate}
+	}
+	return []key.Binding{k.NextField, k.PrevField, k.RGB, k.Animate}
+}
+
+// HintsView lays bindings out on one line as "key desc · key desc", as
+// many as fit in width cells (0 for all). Bindings without help are left
+// out.
+func HintsView(r *lipgloss.Renderer, bindings []key.Binding, width int) string {
+	keyStyle, descStyle := r.NewStyle().Bold(true).Faint(true), r.NewStyle().Faint(true)
+	sep := descStyle.Render(" · ")
+	line := ""
+	for _, b := range bindings {
+		h := b.Help()
+		if !b.Enabled() || h.Key == "" {
+			continue
+		}
+		hint := keyStyle.Render(h.Key) + " " + descStyle.Render(h.Desc)
+		if line != "" {
+			hint = sep + hint
+		}
+		if width > 0 && lipgloss.Width(line+hint) > width {
+			break
+		}
+		line += hint
+	}
+	return line
+}
+
+//------------------------------------------------------------------------------
+// RGB entry (ctrl+t)
+//------------------------------------------------------------------------------
+
+// RGBEntry reports whether colors are entered as R/G/B numbers rather
+// than hex.
+func (m Model) RGBEntry() bool { return m.rgb }
+
+// SetRGBEntry switches the color inputs between hex and three numeric
+// fields, 0 to 255, per color. Both stay in step; only the way of typing
+// changes.
+func (m *Model) SetRGBEntry(on bool) {
+	if on == m.rgb {
+		return
+	}
+	m.rgb = on
+	if on {
+		m.channels = make([]textinput.Model, 6)
+		for i := range m.channels {
+			ti := newTextInput("0", "")
+			ti.CharLimit, ti.Width = 3, 3
+			m.channels[i] = ti
+		}
+		m.hexToChannels()
+	}
+	m.focus(min(m.focusIndex, 1)) // on the first field of the start color
+}
+
+// fields are the inputs tab moves between, in order.
+func (m *Model) fields() []*textinput.Model {
+	fields := []*textinput.Model{&m.inputs[0]}
+	colors := m.inputs[1:]
+	if m.rgb {
+		colors = m.channels
+	}
+	for i := range colors {
+		fields = append(fields, &colors[i])
+	}
+	return fields
+}
+
+// focus moves the focus to field i, wrapping round.
+func (m *Model) focus(i int) {
+	fields := m.fields()
+	m.focusIndex = (i%len(fields) + len(fields)) % len(fields)
+	for j := range m.inputs {
+		m.inputs[j].Blur()
+	}
+	for j := range m.channels {
+		m.channels[j].Blur()
+	}
+	fields[m.focusIndex].Focus()
+}
+
+// hexToChannels fills the numeric fields from the gradient colors.
+func (m *Model) hexToChannels() {
+	for i, c := range []banner.Color{m.baseStart, m.baseEnd} {
+		for j, v := range []int{c.R, c.G, c.B} {
+			m.channels[3*i+j].SetValue(strconv.Itoa(v))
+		}
+	}
+}
+
+// channelsToHex writes the numeric fields back to the hex inputs, keeping
+// only digits and clamping to 255. Empty fields count as 0.
+func (m *Model) channelsToHex() {
+	var v [6]int
+	for i := range m.channels {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, m.channels[i].Value())
+		if digits != m.channels[i].Value() {
+			m.channels[i].SetValue(digits)
+		}
+		n, _ := strconv.Atoi(digits)
+		v[i] = min(n, 255)
+	}
+	for i, in := range []*textinput.Model{&m.inputs[1], &m.inputs[2]} {
+		if hex := (banner.Color{R: v[3*i], G: v[3*i+1], B: v[3*i+2]}).Hex(); !strings.EqualFold(hex, in.Value()) {
+			in.SetValue(hex)
+		}
+	}
+}
+
+// nudgeChannel adds d to numeric field i, within 0 to 255.
+func (m *Model) nudgeChannel(i, d int) {
+	n, _ := strconv.Atoi(m.channels[i].Value())
+	m.channels[i].SetValue(strconv.Itoa(min(255, max(0, n+d))))
+	m.channelsToHex()
+	m.baseStart, _ = banner.ParseHex(m.inputs[1].Value())
+	m.baseEnd, _ = banner.ParseHex(m.inputs[2].Value())
+}
+
+// colorView is the start (end false) or end color's inputs.
+func (m Model) colorView(end bool) string {
+	if !m.rgb {
+		if end {
+			return m.inputs[2].View()
+		}
+		return m.inputs[1].View()
+	}
+	label := m.renderer.NewStyle().Faint(true)
+	from := 0
+	if end {
+		from = 3
+	}
+	var parts []string
+	for i, name := range []string{"R", "G", "B"} {
+		parts = append(parts, label.Render(name)+" "+m.channels[from+i].View())
+	}
+	return strings.Join(parts, "  ")
+}
+
+// EffectiveColors are the gradient endpoints as shown this frame, turned by
+// the hue cycle while it runs.
+func (m Model) EffectiveColors() (start, end banner.Color) {
+	if !m.animate {
+		return m.baseStart, m.baseEnd
+	}
+	return banner.RotateHue(m.baseStart, m.hue.Shift), banner.RotateHue(m.baseEnd, m.hue.Shift)
+}
+
+// FreezeColors stops hue cycling on the colors shown, putting them in the
+// color inputs and stops, and returns the endpoints in hex.
+func (m *Model) FreezeColors() (start, end string) {
+	s, e := m.EffectiveColors()
+	if m.animate {
+		for i := range m.stops {
+			m.stops[i].Color = banner.RotateHue(m.stops[i].Color, m.hue.Shift)
+		}
+	}
+	m.SetAnimate(false)
+	m.hue.Shift = 0
+	_ = m.SetColors(s.Hex(), e.Hex())
+	return s.Hex(), e.Hex()
+}
+
+// Normalization is the Unicode form the text is brought to before
+// composing.
+func (m Model) Normalization() banner.Normalization { return m.norm }
+
+// SetNormalization changes the Unicode form and recomposes.
+func (m *Model) SetNormalization(n banner.Normalization) {
+	if n == m.norm {
+		return
+	}
+	m.norm = n
+	m.artSeq++
+	clear(m.composed)
+	m.built = artKey{}
+	m.rebuildArt()
+	m.gen++
+}
+
+// WordColors are the words colored apart from the gradient.
+func (m Model) WordColors() []banner.WordColor { return m.words }
+
+// SetWordColors replaces the word color rules and recomposes.
+func (m *Model) SetWordColors(words []banner.WordColor) {
+	m.words = words
+	m.artSeq++
+	clear(m.composed)
+	m.built = artKey{}
+	m.rebuildArt()
+	m.gen++
+}
+
+func (m Model) Mode() banner.RenderMode { return m.mode }
+
+// SetMode selects a render mode; nil is ignored.
+func (m *Model) SetMode(mode banner.RenderMode) {
+	if mode != nil {
+		m.mode = mode
+	}
+}
+
+func (m Model) Animating() bool { return m.animate }
+
+// SetAnimate starts or stops hue cycling. The returned command must be run
+// for a started animation to tick.
+func (m *Model) SetAnimate(on bool) tea.Cmd {
+	if on == m.animate {
+		return nil
+	}
+	m.animate = on
+	if on {
+		return m.restartTick()
+	}
+	return nil
+}
+
+// Step is the hue cycle speed in degrees per tick.
+func (m Model) Step() float64 { return m.hue.Step }
+
+func (m *Model) SetStep(deg float64) { m.hue.Step = math.Max(0.5, math.Min(30, deg)) }
+
+// Effects returns the effect stack, hue cycling first.
+func (m Model) Effects() []banner.Effect { return append([]banner.Effect(nil), m.effects...) }
+
+// AddEffect pushes e onto the effect stack.
+func (m *Model) AddEffect(e banner.Effect) {
+	e.Init(m.art.Width, len(m.art.Rows))
+	if t, ok := e.(banner.Timed); ok {
+		t.SetTickDuration(m.interval)
+	}
+	m.effects = append(m.effects, e)
+	m.gen++
+}
+
+// RemoveEffect drops e from the stack. The hue cycle cannot be removed;
+// stop the animation instead.
+func (m *Model) RemoveEffect(e banner.Effect) {
+	kept := m.effects[:0:0]
+	for _, have := range m.effects {
+		if have != e || have == banner.Effect(m.hue) {
+			kept = append(kept, have)
+		}
+	}
+	m.effects = kept
+	m.gen++
+}
+
+// Interval is the chosen tick interval; see TickInterval.
+func (m Model) Interval() time.Duration { return m.interval }
+
+// TickInterval is the interval actually used: Interval, stretched while the
+// banner is too big or slow to draw at that rate.
+func (m Model) TickInterval() time.Duration {
+	d := m.interval
+	if cost := m.art.Width * len(m.art.Rows) * len(m.effects); cost > cellBudget {
+		d = time.Duration(float64(d) * float64(cost) / cellBudget)
+	}
+	d = max(d, m.draw.took*2)
+	return min(d, max(maxInterval, m.interval))
+}
+
+// SetInterval changes the tick interval; it applies from the next tick.
+func (m *Model) SetInterval(d time.Duration) {
+	if d > 0 {
+		m.interval = d
+	}
+	for _, e := range m.effects {
+		if t, ok := e.(banner.Timed); ok {
+			t.SetTickDuration(m.interval)
+		}
+	}
+}
+
+// Renderer is the lipgloss renderer the views are styled with.
+func (m Model) Renderer() *lipgloss.Renderer { return m.renderer }
+
+// SetRenderer styles the views for another output, such as an SSH session;
+// nil is ignored.
+func (m *Model) SetRenderer(r *lipgloss.Renderer) {
+	if r != nil {
+		m.renderer = r
+	}
+}
+
+// SetDebugLog logs compose and draw timings, and font errors, to l; nil
+// turns the log off.
+func (m *Model) SetDebugLog(l *log.Logger) { m.debug = l }
+
+func (m Model) debugf(format string, args ...any) {
+	if m.debug != nil {
+		m.debug.Printf(format, args...)
+	}
+}
+
+// Err reports why the current text/font could not be composed, if it
+// could not.
+func (m Model) Err() error { return m.err }
+
+// FontErr reports why the current font failed to load, if it did; the
+// banner is then shown in the fallback font.
+func (m Model) FontErr() error { return m.fontErr }
+
+// Frame returns the banner as currently displayed.
+func (m Model) Frame() banner.Frame {
+	var f banner.Frame
+	return m.render(&f)
+}
+
+// render colors the art into f and applies the running effects.
+func (m Model) render(f *banner.Frame) banner.Frame {
+	m.art.ColorizeInto(f, banner.Options{Font: m.Font(), Start: m.baseStart, End: m.baseEnd, Stops: m.stops, Mode: m.mode})
+	if m.animate {
+		return banner.ApplyEffects(*f, m.effects)
+	}
+	return *f
+}
+
+//------------------------------------------------------------------------------
+// Bubble Tea
+//------------------------------------------------------------------------------
+
+func (m *Model) restartTick() tea.Cmd {
+	m.tag++
+	return m.tick()
+}
+
+func (m Model) tick() tea.Cmd {
+	id, tag := m.id, m.tag
+	return tea.Tick(m.TickInterval(), func(time.Time) tea.Msg { return TickMsg{ID: id, tag: tag} })
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.animate {
+		return m.tick()
+	}
+	return nil
+}
+
+// Update implements tea.Model; the returned model is always a Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.idleFor = artKey{} // not idle: start the wait over
+	}
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.prerender())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		k := m.keys
+		switch {
+		case key.Matches(msg, k.Select):
+			m.rebuildArt() // don't select stale art mid-debounce
+			return m, m.selected
+		case key.Matches(msg, k.NextField):
+			m.focus(m.focusIndex + 1)
+			return m, nil
+		case key.Matches(msg, k.PrevField):
+			m.focus(m.focusIndex - 1)
+			return m, nil
+		case key.Matches(msg, k.RGB):
+			m.SetRGBEntry(!m.rgb)
+			return m, nil
+		case key.Matches(msg, k.Channel) && m.rgb && m.focusIndex > 0:
+			step := map[string]int{"up": 1, "down": -1, "shift+up": 16, "shift+down": -16}[msg.String()]
+			m.nudgeChannel(m.focusIndex-1, step)
+			return m, nil
+		case key.Matches(msg, k.PrevFont):
+			m.SkipFonts(-1)
+			return m, nil
+		case key.Matches(msg, k.NextFont):
+			m.SkipFonts(1)
+			return m, nil
+		case key.Matches(msg, k.Mode):
+			m.mode = banner.NextMode(m.mode)
+			return m, nil
+		case key.Matches(msg, k.Animate):
+			return m, m.SetAnimate(!m.animate)
+		case key.Matches(msg, k.Faster):
+			m.SetStep(m.hue.Step + 0.5)
+			return m, nil
+		case key.Matches(msg, k.Slower):
+			m.SetStep(m.hue.Step - 0.5)
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		m.SetWidth(msg.Width)
+		return m, nil
+	case TickMsg:
+		if msg.ID != m.id || msg.tag != m.tag {
+			return m, nil
+		}
+		if m.animate {
+			for _, e := range m.effects {
+				e.Tick()
+			}
+			m.ticks++
+			if len(m.sequence) > 0 {
+				m.played += m.interval
+				m.rebuildArt()
+			}
+			return m, m.tick()
+		}
+		return m, nil
+	case RebuildMsg:
+		if msg.ID == m.id && msg.seq == m.typingSeq {
+			m.rebuildArt()
+		}
+		return m, nil
+	case PrerenderMsg:
+		if msg.ID != m.id || msg.seq != m.idleSeq {
+			return m, nil
+		}
+		return m, m.composeNeighbours()
+	case PrerenderedMsg:
+		if msg.ID == m.id && msg.seq == m.artSeq && msg.key.text == m.built.text {
+			m.composed[msg.key] = msg.art
+		}
+		return m, nil
+	}
+
+	// Typed and pasted text loses control characters and escape sequences
+	// before any input sees it
+	before := m.inputs[0].Value()
+	dirty := false
+	if k, ok := msg.(tea.KeyMsg); ok && k.Type == tea.KeyRunes {
+		if clean, changed := banner.Sanitize(string(k.Runes)); changed {
+			k.Runes, dirty = []rune(clean), true
+			msg = k
+		}
+	}
+
+	// Update inputs and live-apply changes
+	var cmds []tea.Cmd
+	for _, in := range m.fields() {
+		var cmd tea.Cmd
+		*in, cmd = in.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.rgb {
+		m.channelsToHex()
+	}
+
+	if m.inputs[0].Value() != before {
+		m.sanitized = dirty
+	}
+
+	// Text changes rebuild art once typing pauses
+	if m.inputs[0].Value() != m.built.text {
+		m.typingSeq++
+		id, seq := m.id, m.typingSeq
+		cmds = append(cmds, tea.Tick(typingDebounce, func(time.Time) tea.Msg { return RebuildMsg{ID: id, seq: seq} }))
+	}
+
+	// Colors update when valid (these are bases for hue rotation)
+	if c, ok := banner.ParseHex(m.inputs[1].Value()); ok {
+		m.baseStart = c
+	}
+	if c, ok := banner.ParseHex(m.inputs[2].Value()); ok {
+		m.baseEnd = c
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// prerender schedules composing the neighbouring fonts after
+// prerenderIdle, unless it is already scheduled for the current art.
+func (m *Model) prerender() tea.Cmd {
+	if m.err != nil || m.built == m.idleFor {
+		return nil
+	}
+	m.idleFor = m.built
+	m.idleSeq++
+	id, seq := m.id, m.idleSeq
+	return tea.Tick(prerenderIdle, func(time.Time) tea.Msg { return PrerenderMsg{ID: id, seq: seq} })
+}
+
+// composeNeighbours composes the current text in the fonts around the
+// current one that aren't cached yet, each in its own goroutine, so
+// cycling fonts doesn't wait on big ones.
+func (m Model) composeNeighbours() tea.Cmd {
+	var cmds []tea.Cmd
+	n := len(m.fonts)
+	for d := 1; d <= prerenderSpan; d++ {
+		for _, i := range []int{m.fontIndex + d, m.fontIndex - d} {
+			key := artKey{m.built.text, m.fonts[(i%n+n)%n]}
+			if _, ok := m.composed[key]; ok {
+				continue
+			}
+			id, seq, norm, words := m.id, m.artSeq, m.norm, m.words
+			cmds = append(cmds, func() tea.Msg {
+				art, err := compose(key.text, key.font, norm, words)
+				if err != nil {
+					return nil // shown if the user gets there
+				}
+				return PrerenderedMsg{ID: id, seq: seq, key: key, art: art}
+			})
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) selected() tea.Msg {
+	if m.err != nil {
+		return nil
+	}
+	font := m.Font()
+	if m.fontErr != nil {
+		font = fallbackFont
+	}
+	return SelectedMsg{Text: m.Text(), Font: font, Frame: m.Frame()}
+}
+
+// ControlsView renders the settings panel.
+func (m Model) ControlsView() string {
+	labelStyle := m.renderer.NewStyle().Faint(true)
+	box := m.renderer.NewStyle().Padding(0, 1).Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+
+	animState := "off"
+	if m.animate {
+		animState = fmt.Sprintf("on (%.1f°/tick)", m.hue.Step)
+		if d := m.TickInterval(); d > m.interval {
+			animState = fmt.Sprintf("on (%.1f°/tick, slowed to %.0f fps)", m.hue.Step, float64(time.Second)/float64(d))
+		}
+	}
+	textChips := ""
+	if m.sanitized {
+		textChips = " " + ChipWith(m.renderer, "sanitized", "230", "94")
+	}
+	font := m.Font()
+	if m.fontErr != nil {
+		font = fallbackFont
+	}
+	if _, kept := fitText(m.built.text, font); kept >= 0 {
+		textChips += " " + ChipWith(m.renderer, fmt.Sprintf("too long: showing %d of %d", kept, utf8.RuneCountInString(m.built.text)), "230", "94")
+	}
+	ctrlLines := []string{
+		labelStyle.Render("Text:") + " " + m.inputs[0].View() + textChips,
+		labelStyle.Render("Start:") + " " + m.colorView(false),
+		labelStyle.Render("End:") + " " + m.colorView(true),
+		labelStyle.Render("Font:") + " " + ChipWith(m.renderer, m.Font(), "212", "57"),
+		labelStyle.Render("Mode:") + " " + ChipWith(m.renderer, banner.ModeLabel(m.mode), "118", "237"),
+		labelStyle.Render("Hue cycle:") + " " + ChipWith(m.renderer, animState, "51", "240"),
+	}
+	if m.err != nil {
+		ctrlLines = append(ctrlLines, ChipWith(m.renderer, "error: "+m.err.Error(), "230", "124"))
+	} else if m.fontErr != nil {
+		ctrlLines = append(ctrlLines, ChipWith(m.renderer, "error: "+m.fontErr.Error()+" (showing "+fallbackFont+")", "230", "124"))
+	}
+	return box.Render(strings.Join(ctrlLines, "\n"))
+}
+
+// PreviewView renders the colored banner, wrapped between letters into
+// bands when it is wider than SetWidth allows. Between changes, such as on
+// cursor blinks, the last preview is returned as is.
+func (m Model) PreviewView() string {
+	key := drawKey{
+		art: m.built, start: m.baseStart, end: m.baseEnd, mode: m.mode.Name(),
+		animate: m.animate, ticks: m.ticks, gen: m.gen, renderer: m.renderer,
+		cursor: m.cursor, cursorOn: m.cursorOn, width: m.width,
+	}
+	d := m.draw
+	if key == d.key && d.out != "" {
+		return d.out
+	}
+	start := time.Now()
+	f := m.render(&d.frame)
+	if x, y := m.cursor[0], m.cursor[1]; m.cursorOn && y >= 0 && y < len(f.Cells) && x >= 0 && x < len(f.Cells[y]) {
+		c := &f.Cells[y][x]
+		if c.Blank {
+			c.Rune, c.Blank = '_', false
+		}
+		c.FG = banner.Color{R: 255, G: 255, B: 255}
+	}
+	if !m.cursorOn { // the cursor is placed in unwrapped art
+		f = f.Wrap(m.width)
+	}
+	d.out = f.Styled(m.renderer)
+	d.key = key
+	d.took = time.Since(start)
+	m.debugf("draw %dx%d in %v", m.art.Width, len(m.art.Rows), d.took)
+	return d.out
+}
+
+// View stacks the controls above the preview, with key hints under it.
+func (m Model) View() string {
+	return m.ControlsView() + "\n" + m.PreviewView() + "\n" + HintsView(m.renderer, m.KeyHints(), m.width)
+}
+
+// Chip renders a short label on a colored background.
+func Chip(name, fg, bg string) string { return ChipWith(lipgloss.DefaultRenderer(), name, fg, bg) }
+
+// ChipWith is Chip styled for r.
+func ChipWith(r *lipgloss.Renderer, name, fg, bg string) string {
+	return r.NewStyle().Foreground(lipgloss.Color(fg)).Background(lipgloss.Color(bg)).Padding(0, 1).Render(name)
+}
@@ -0,0 +1,65 @@
+package viewer
+
+import (
+	"io"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"glamdm/pkg/banner"
+)
+
+// TestPreviewRedrawn checks that setters whose changes drawKey doesn't
+// see still redraw the preview when the animation is off.
+func TestPreviewRedrawn(t *testing.T) {
+	red := banner.Color{R: 255}
+	tests := []struct {
+		name, text string
+		set        func(m *Model)
+	}{
+		{"stops", "Hi", func(m *Model) { m.SetStops([]banner.Stop{{At: 0.5, Color: red}}) }},
+		{"word colors", "Hi", func(m *Model) { m.SetWordColors([]banner.WordColor{{Word: "Hi", Color: red}}) }},
+		{"normalization", "café", func(m *Model) { m.SetNormalization(banner.StripMarks) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			r := lipgloss.NewRenderer(io.Discard)
+			r.SetColorProfile(termenv.TrueColor)
+			m.SetRenderer(r)
+			m.SetText(tt.text)
+			before := m.PreviewView()
+			tt.set(&m)
+			if m.PreviewView() == before {
+				t.Error("preview unchanged")
+			}
+		})
+	}
+}
+
+// TestPrerenderedStale checks that fonts composed in the background with
+// the word colors since replaced are dropped.
+func TestPrerenderedStale(t *testing.T) {
+	m := New()
+	m.SetText("Hi")
+	prerendered := func() PrerenderedMsg {
+		batch := m.composeNeighbours()().(tea.BatchMsg)
+		return batch[0]().(PrerenderedMsg)
+	}
+
+	stale := prerendered()
+	m.SetWordColors([]banner.WordColor{{Word: "Hi", Color: banner.Color{R: 255}}})
+	next, _ := m.Update(stale)
+	m = next.(Model)
+	if _, ok := m.composed[stale.key]; ok {
+		t.Fatal("kept art composed before the word colors changed")
+	}
+
+	fresh := prerendered()
+	next, _ = m.Update(fresh)
+	if _, ok := next.(Model).composed[fresh.key]; !ok {
+		t.Fatal("dropped current art")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Hand-made animations (--play PATH)
+//------------------------------------------------------------------------------
+
+const (
+	defaultPlaySep = "---"
+	defaultPlayFPS = 8
+)
+
+// sequence is a --play animation for render: its frames, each held for
+// hold output frames. It is empty without --play.
+type sequence struct {
+	frames []banner.Art
+	hold   int
+}
+
+// loadSequence reads the --play frames for output at fps.
+func loadSequence(c cliFlags, fps float64) (sequence, error) {
+	if c.play == "" {
+		return sequence{}, nil
+	}
+	frames, err := playFrames(c)
+	if err != nil {
+		return sequence{}, err
+	}
+	if fps <= 0 {
+		fps = 15
+	}
+	return sequence{frames, max(1, int(math.Round(fps/c.playFPS)))}, nil
+}
+
+// playFrames reads the --play frames.
+func playFrames(c cliFlags) ([]banner.Art, error) {
+	if c.playFPS <= 0 {
+		return nil, fmt.Errorf("play-fps %g must be positive", c.playFPS)
+	}
+	frames, err := banner.LoadSequence(c.play, c.playSep)
+	if err != nil {
+		return nil, fmt.Errorf("play: %w", err)
+	}
+	return frames, nil
+}
+
+// playEvery is how long each --play frame shows in the TUI.
+func playEvery(c cliFlags) time.Duration { return time.Duration(float64(time.Second) / c.playFPS) }
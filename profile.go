@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime/trace"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Profiling (--pprof ADDR, --trace FILE)
+//------------------------------------------------------------------------------
+
+// listenPprof serves the net/http/pprof endpoints under /debug/pprof/ on
+// addr, on a mux of its own so they never leak onto the public servers. An
+// empty addr does nothing.
+func listenPprof(addr string) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	go func() {
+		if err := (&http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}).ListenAndServe(); err != nil {
+			log.Printf("pprof: %v", err)
+		}
+	}()
+}
+
+// startTrace records an execution trace into path until the returned func
+// is called. An empty path does nothing.
+func startTrace(path string) (func(), error) {
+	if path == "" {
+		return func() {}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		trace.Stop()
+		f.Close()
+	}, nil
+}
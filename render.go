@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"glamdm/pkg/banner"
+	"glamdm/pkg/colorexpr"
+	"glamdm/pkg/luaeffect"
+)
+
+//------------------------------------------------------------------------------
+// render subcommand (headless)
+//------------------------------------------------------------------------------
+
+// renderFormats lists render's --format values.
+var renderFormats = []string{"ansi", "json", "html", "svg", "irc", "markdown", "discord", "ans", "bash", "powershell", "python", "bash-prompt", "zsh-prompt", "sixel", "kitty", "iterm2", "png", "gif", "apng", "webp"}
+
+// outputFormats are the --format values --output picks by file extension.
+var outputFormats = map[string]string{
+	".ans": "ans", ".json": "json", ".html": "html", ".htm": "html", ".svg": "svg",
+	".md": "markdown", ".sh": "bash", ".ps1": "powershell", ".py": "python", ".six": "sixel",
+	".png": "png", ".gif": "gif", ".apng": "apng", ".webp": "webp",
+}
+
+// outputFormat is the format for writing path, from its extension: ansi
+// for stdout or an extension not in outputFormats.
+func outputFormat(path string) string {
+	if f, ok := outputFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return f
+	}
+	return "ansi"
+}
+
+// runRender prints one frame without starting the TUI. Output is
+// deterministic: fixed canvas size, fixed hue shift, and either truecolor
+// escapes, an .ans file, structured JSON cells, IRC color codes, Markdown
+// code blocks, a script printing the banner or an image (sixel, kitty,
+// iTerm2 or PNG). GIF, APNG and WebP are animated. With --output the
+// result replaces the file in one step instead of going to stdout.
+func runRender(global cliFlags, args []string) (err error) {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags := global
+	flags.register(fs)
+	width := fs.Int("width", 0, "center on a canvas this many columns wide (needs --height)")
+	height := fs.Int("height", 0, "center on a canvas this many rows tall (needs --width)")
+	hue := fs.Float64("hue", 0, "hue shift in degrees, standing in for animation time")
+	format := fs.String("format", "", "output format: "+strings.Join(renderFormats, ", ")+" (default from the --output extension, else ansi)")
+	ircColors := fs.Int("irc-colors", 99, "colors for irc: 99, or 16 for older clients")
+	limit := fs.Int("limit", banner.DiscordLimit, "split markdown and discord output into messages of at most this many characters (0 = never)")
+	iterm2 := fs.Bool("iterm2", false, "shorthand for --format iterm2")
+	trim := fs.Bool("trim", false, "drop trailing blanks from each line")
+	maxWidth := fs.Int("max-width", 0, "limit lines to this many cells (0 = no limit)")
+	overflow := fs.String("overflow", "truncate", "what --max-width does to longer art: truncate or wrap")
+	eol := fs.String("eol", "lf", "line endings for text formats: lf or crlf")
+	sauce := fs.Bool("sauce", false, "append a SAUCE record to ans output")
+	title := fs.String("title", "", "SAUCE title (default: the text)")
+	author := fs.String("author", "", "SAUCE author")
+	group := fs.String("group", "", "SAUCE group")
+	scale := fs.Int("scale", 2, "pixel scale for image formats: each cell is 4N by 8N pixels")
+	stream := fs.Bool("stream", false, "stream animated frames instead of printing one")
+	var bubble banner.Bubble
+	fs.Var(optionalValue{&bubble.Mascot, "cow"}, "say", "put the art in a speech bubble said by a mascot: --say=MASCOT picks one of "+strings.Join(banner.MascotNames(), ", ")+" or none")
+	think := fs.Bool("think", false, "with --say, a thought bubble instead")
+	warp := fs.String("warp", "", "bend the art along a curve: "+strings.Join(banner.WarpShapes, " or "))
+	warpAmount := fs.Float64("warp-amount", 2, "rows --warp rises at its peak; negative bends it the other way")
+	skew := fs.Float64("skew", 0, "lean the art this many columns right per row, e.g. 0.5 for italics; negative leans left")
+	perspective := fs.Float64("perspective", 0, "narrow the top of the art by this fraction (0-0.9), or the bottom if negative")
+	imageWidth := fs.Int("image-width", 80, "width of --image art in cells")
+	fps := fs.Float64("fps", 15, "frames per second when streaming or animating")
+	frames := fs.Int("frames", 0, "stop streaming after this many frames (0 = until interrupted); for gif, apng and webp, the frame count (0 = one hue cycle)")
+	output := fs.String("output", "", "write to this file instead of stdout, replacing it only once complete, as cron jobs want")
+	quiet := fs.Bool("quiet", false, "with --output, don't report the file written")
+	job := fs.String("json", "", "read the flags from a JSON object in this file, - for stdin, e.g. {\"text\": \"hi\", \"format\": \"png\"}; flags given here win")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *job != "" {
+		if err := applyJob(fs, *job); err != nil {
+			return err
+		}
+	}
+	if err := envFlags(fs); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		flags.values["text"] = fs.Arg(0)
+	}
+	if !(math.Abs(*perspective) <= banner.MaxPerspective) {
+		return fmt.Errorf("perspective %g out of range (-%g to %g)", *perspective, banner.MaxPerspective, banner.MaxPerspective)
+	}
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, ferr := createAtomic(*output)
+		if ferr != nil {
+			return ferr
+		}
+		w = file
+		defer func() {
+			if err = file.finish(err); err == nil && !*quiet {
+				fmt.Fprintln(os.Stderr, "wrote", *output)
+			}
+		}()
+	}
+	if *iterm2 {
+		*format = "iterm2"
+	}
+	if *format == "" {
+		*format = outputFormat(*output)
+	}
+	if bubble.Mascot != "" {
+		bubble.Style = "say"
+		if *think {
+			bubble.Style = "think"
+		}
+		if bubble.Mascot == "none" {
+			bubble.Mascot = ""
+		}
+	}
+
+	s, err := loadSettings(flags)
+	if err != nil {
+		return err
+	}
+	var script []banner.Effect
+	if flags.effect != "" {
+		e, err := loadEffect(flags.effect)
+		if err != nil {
+			return err
+		}
+		defer closeEffect(e)
+		script = append(script, e)
+	}
+	if flags.color != "" {
+		e, err := colorexpr.Parse(flags.color)
+		if err != nil {
+			return fmt.Errorf("color: %w", err)
+		}
+		script = append(script, e)
+	}
+	if err := checkImageStyle(flags.imageStyle); err != nil {
+		return err
+	}
+	if err := checkArtSource(flags); err != nil {
+		return err
+	}
+	if flags.image != "" && flags.imageStyle == "color" {
+		return renderPicture(w, flags.image, *imageWidth, *format, flags.legacyConsole())
+	}
+	o := s.options(*hue)
+	o.Bubble = bubble
+	o.Warp = banner.Warp{Shape: *warp, Amount: *warpAmount}
+	o.Skew, o.Perspective = *skew, *perspective
+	art, err := renderArt(s.Text, o, flags, *imageWidth)
+	if err != nil {
+		return err
+	}
+	seq, err := loadSequence(flags, *fps)
+	if err != nil {
+		return err
+	}
+	if len(seq.frames) > 0 {
+		art = seq.frames[0]
+	}
+	*frames = flags.limitFrames(*frames, s.StepDeg, seq, *fps)
+	if *stream && *frames == 0 && *output != "" {
+		return fmt.Errorf("--output with --stream needs --frames, --loops or --duration")
+	}
+	if *stream {
+		return streamRender(w, art, seq, o, s.StepDeg, script, *fps, *frames, *width, *height, flags.legacyConsole())
+	}
+	switch *format {
+	case "gif", "apng", "webp":
+		return animateRender(w, art, seq, o, s.StepDeg, script, *format, *fps, *frames, *width, *height, *scale)
+	}
+
+	for _, e := range script {
+		e.Init(art.Width, len(art.Rows))
+	}
+	f := banner.ApplyEffects(art.Colorize(o), script)
+	if err := scriptErr(script); err != nil {
+		return err
+	}
+	if *width > 0 && *height > 0 {
+		f = f.Place(*width, *height)
+	}
+	bd, err := loadBackdrop(flags)
+	if err != nil {
+		return err
+	}
+	if bd != nil {
+		f = bd.behind(f, *width, *height)
+	}
+	switch *overflow {
+	case "truncate":
+		f = f.Truncate(*maxWidth)
+	case "wrap":
+		f = f.Wrap(*maxWidth)
+	default:
+		return fmt.Errorf("unknown overflow %q (want truncate or wrap)", *overflow)
+	}
+	if *trim {
+		f = f.TrimRight()
+	}
+	if *eol != "lf" && *eol != "crlf" {
+		return fmt.Errorf("unknown eol %q (want lf or crlf)", *eol)
+	}
+
+	legacy := flags.legacyConsole()
+	var out string
+	switch *format {
+	case "ansi":
+		out = f.ANSI()
+		if legacy {
+			out = f.Styled(legacyRenderer())
+		}
+	case "json":
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		out = string(b)
+	case "html":
+		out = htmlPage(s.Text, f)
+	case "svg":
+		out = f.SVG()
+	case "irc":
+		if *ircColors != 16 && *ircColors != 99 {
+			return fmt.Errorf("irc-colors must be 16 or 99")
+		}
+		out = f.IRC(*ircColors == 99)
+	case "markdown", "discord":
+		out = strings.Join(f.Markdown(*format == "discord", *limit), "\n\n")
+	case "bash":
+		out = f.Bash()
+	case "powershell":
+		out = f.PowerShell()
+	case "python":
+		out = f.Python()
+	case "bash-prompt":
+		out = f.BashPrompt()
+	case "zsh-prompt":
+		out = f.ZshPrompt()
+	case "sixel":
+		out = f.Sixel(*scale)
+	case "kitty":
+		if out, err = f.Kitty(*scale); err != nil {
+			return err
+		}
+	case "iterm2":
+		if out, err = f.ITerm2(*scale); err != nil {
+			return err
+		}
+	case "ans":
+		var rec *banner.Sauce
+		if *sauce {
+			rec = &banner.Sauce{Title: *title, Author: *author, Group: *group, Date: time.Now()}
+			if rec.Title == "" {
+				rec.Title = s.Text
+			}
+		}
+		_, err = w.Write(f.ANS(rec)) // ends in CRLF or the record
+		return err
+	case "png":
+		b, err := f.PNG(*scale)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b) // binary: no trailing newline
+		return err
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", *format, strings.Join(renderFormats, ", "))
+	}
+	if legacy || *eol == "crlf" {
+		_, err = fmt.Fprint(crlfWriter{w}, out+"\n")
+		return err
+	}
+	_, err = fmt.Fprintln(w, out)
+	return err
+}
+
+// htmlPage is f as a standalone page on black, titled with the text.
+func htmlPage(title string, f banner.Frame) string {
+	return "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>" + html.EscapeString(title) +
+		"</title></head>\n<body style=\"background:#000\">" + f.HTML() + "</body></html>"
+}
+
+// renderArt composes text with o, or takes the --art art, or draws the
+// --image picture width cells wide; either is transformed as o says. --play is loaded by loadSequence.
+func renderArt(text string, o banner.Options, flags cliFlags, width int) (banner.Art, error) {
+	switch {
+	case flags.art != "":
+		art, err := loadArt(flags.art)
+		if err != nil {
+			return banner.Art{}, fmt.Errorf("art: %w", err)
+		}
+		return art.Transformed(o)
+	case flags.image == "":
+		return banner.Compose(text, banner.WithOptions(o))
+	}
+	img, err := banner.LoadImage(flags.image)
+	if err != nil {
+		return banner.Art{}, fmt.Errorf("image: %w", err)
+	}
+	return banner.ImageArt(img, width).Transformed(o)
+}
+
+// renderPicture writes the image at path to w in color half blocks. Only
+// ansi output has the background colors they need.
+func renderPicture(w io.Writer, path string, width int, format string, legacy bool) error {
+	if format != "ansi" {
+		return fmt.Errorf("--image-style color only renders as ansi")
+	}
+	img, err := banner.LoadImage(path)
+	if err != nil {
+		return fmt.Errorf("image: %w", err)
+	}
+	p := banner.NewPicture(img, width)
+	if legacy {
+		_, err = fmt.Fprint(crlfWriter{w}, p.Styled(legacyRenderer())+"\n")
+		return err
+	}
+	_, err = fmt.Fprintln(w, p.ANSI())
+	return err
+}
+
+// legacyRenderer styles output in the 16 colors a legacy Windows console
+// shows.
+func legacyRenderer() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(os.Stdout)
+	r.SetColorProfile(termenv.ANSI)
+	return r
+}
+
+// streamRender plays the hue animation on out until interrupted. For a
+// legacy console, frames use 16 colors and CRLF line ends.
+func streamRender(out io.Writer, art banner.Art, seq sequence, o banner.Options, step float64, script []banner.Effect, fps float64, frames, width, height int, legacy bool) error {
+	opts := banner.WithOptions(o)
+	effects := append([]banner.Effect{banner.NewHueCycle(step)}, script...)
+	w := out
+	if legacy {
+		w = crlfWriter{out}
+	}
+	aw := banner.NewAnimationWriter(w, art, opts, banner.WithEffect(effects...))
+	if legacy {
+		aw.Renderer = legacyRenderer()
+	}
+	aw.FPS, aw.Frames = fps, frames
+	aw.Width, aw.Height = width, height
+	aw.Sequence, aw.Hold = seq.frames, seq.hold
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := aw.Run(ctx); err != nil {
+		return err
+	}
+	return scriptErr(script)
+}
+
+// animateRender writes the hue animation to out as an animated image,
+// for the effects, or the --play sequence, to loop once if frames is 0.
+func animateRender(out io.Writer, art banner.Art, seq sequence, o banner.Options, step float64, script []banner.Effect, format string, fps float64, frames, width, height, scale int) error {
+	opts := banner.WithOptions(o)
+	if frames <= 0 {
+		frames = loopFrames(step, seq)
+	}
+	if fps <= 0 {
+		fps = 15
+	}
+	effects := append([]banner.Effect{banner.NewHueCycle(step)}, script...)
+	aw := banner.NewAnimationWriter(io.Discard, art, opts, banner.WithEffect(effects...))
+	aw.Width, aw.Height = width, height
+	aw.Sequence, aw.Hold = seq.frames, seq.hold
+	captured := aw.Capture(frames)
+	if err := scriptErr(script); err != nil {
+		return err
+	}
+
+	encode := map[string]func(io.Writer, []banner.Frame, time.Duration, int) error{
+		"gif": banner.GIF, "apng": banner.APNG, "webp": banner.WebP,
+	}[format]
+	w := bufio.NewWriter(out)
+	if err := encode(w, captured, time.Duration(float64(time.Second)/fps), scale); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// scriptErr reports a Lua effect that failed while rendering.
+func scriptErr(script []banner.Effect) error {
+	for _, e := range script {
+		if l, ok := e.(*luaeffect.Effect); ok && l.Err() != nil {
+			return l.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Reset (ctrl+x, ":reset")
+//------------------------------------------------------------------------------
+
+// confirmation is a question in the status line waiting for an answer:
+// one of the keys in answers, or any other key to cancel.
+type confirmation struct {
+	question string
+	answers  map[string]func(m *model) (string, tea.Cmd)
+}
+
+// ask puts a yes/no question to the user; y runs yes.
+func (m *model) ask(question string, yes func(m *model) (string, tea.Cmd)) {
+	m.confirm = &confirmation{question: question + " (y/n)", answers: map[string]func(*model) (string, tea.Cmd){"y": yes, "Y": yes}}
+}
+
+// updateConfirm answers the open question with msg.
+func (m model) updateConfirm(msg tea.KeyMsg) (model, tea.Cmd) {
+	answer, ok := m.confirm.answers[msg.String()]
+	m.confirm = nil
+	if !ok {
+		m.status = "cancelled"
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.status, cmd = answer(&m)
+	return m, cmd
+}
+
+// askReset offers to go back to the preset, or with defaults, to the
+// built-in defaults, keeping the text. It returns the status to show.
+func (m *model) askReset(defaults bool) string {
+	target, question := m.preset, "reset to the settings last loaded?"
+	if defaults {
+		target, question = defaultSettings(), "reset everything but the text to the defaults?"
+		target.Text = m.v.Text()
+	}
+	if target == m.settings() {
+		return "nothing to reset"
+	}
+	m.ask(question, func(m *model) (string, tea.Cmd) {
+		return "settings reset", m.applySettings(target)
+	})
+	return ""
+}
+
+func cmdReset(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 0:
+		return m.askReset(false), nil
+	case len(args) == 1 && args[0] == "defaults":
+		return m.askReset(true), nil
+	}
+	return "usage: reset | reset defaults", nil
+}
+
+//------------------------------------------------------------------------------
+// Quitting with unsaved changes
+//------------------------------------------------------------------------------
+
+// unsaved reports whether the look differs from the preset. Text a feed
+// writes doesn't count.
+func (m model) unsaved() bool {
+	s := m.settings()
+	if m.feed != nil {
+		s.Text = m.preset.Text
+	}
+	return s != m.preset
+}
+
+// quit quits, or with unsaved changes asks first: y quits anyway, s
+// saves the look as a theme and then quits, and ctrl+c, pressed again,
+// always quits.
+func (m *model) quit() tea.Cmd {
+	if !m.unsaved() {
+		return tea.Quit
+	}
+	quit := func(*model) (string, tea.Cmd) { return "", tea.Quit }
+	c := &confirmation{question: "quit without saving changes? (y/n)", answers: map[string]func(*model) (string, tea.Cmd){"y": quit, "Y": quit, "ctrl+c": quit}}
+	if !m.public {
+		c.question = "quit without saving changes? (y/n, s to save as a theme first)"
+		c.answers["s"] = func(m *model) (string, tea.Cmd) {
+			m.quitAfterSave = true
+			cmd := m.openCommandLine()
+			m.cmdline.SetValue("theme save ")
+			m.cmdline.CursorEnd()
+			return "", cmd
+		}
+	}
+	m.confirm = c
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+// REST update API (POST /text, POST /theme)
+//------------------------------------------------------------------------------
+
+// The update API lets chat bots and scripts change the live banner that
+// /ws (and stream overlays) show. Every request needs
+// "Authorization: Bearer TOKEN"; without --token the endpoints are off.
+// With a token, /ws clients need it too to send updates.
+
+const maxRESTBody = 4096
+
+// hasToken reports whether r carries token, as "Authorization: Bearer
+// TOKEN" or, for browser WebSockets, which can't set headers, ?token=TOKEN.
+func hasToken(r *http.Request, token string) bool {
+	want := []byte(token)
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		got = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), want) == 1
+}
+
+// authorized wraps next with a bearer token check.
+func authorized(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" || !hasToken(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// postValue reads the single value a request carries: a JSON body such as
+// {"text":"..."}, a form field named key, or else the raw body as plain
+// text.
+func postValue(r *http.Request, key string) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRESTBody))
+	if err != nil {
+		return "", err
+	}
+	switch ct := r.Header.Get("Content-Type"); {
+	case strings.HasPrefix(ct, "application/json"):
+		var v map[string]string
+		if err := json.Unmarshal(body, &v); err != nil {
+			return "", err
+		}
+		return v[key], nil
+	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"):
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		if err := r.ParseForm(); err != nil {
+			return "", err
+		}
+		if r.PostForm.Has(key) {
+			return r.PostForm.Get(key), nil
+		}
+		// curl -d 'HELLO' says form but means plain text.
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// postText handles POST /text: replace the live banner's text.
+func (srv *renderServer) postText(w http.ResponseWriter, r *http.Request) {
+	text, err := postValue(r, "text")
+	if err == nil && text == "" {
+		err = errors.New("missing text")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	srv.reply(w, srv.live.update(map[string]string{"text": text}))
+}
+
+// postTheme handles POST /theme: apply a saved theme, keeping the text.
+func (srv *renderServer) postTheme(w http.ResponseWriter, r *http.Request) {
+	name, err := postValue(r, "theme")
+	if err == nil && name == "" {
+		err = errors.New("missing theme")
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	srv.reply(w, srv.live.change(func(s *settings) error { return applyTheme(s, name) }))
+}
+
+func (srv *renderServer) reply(w http.ResponseWriter, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// serve subcommand
+//------------------------------------------------------------------------------
+
+// runServe dispatches "serve KIND [flags]".
+func runServe(global cliFlags, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: serve http|overlay|ssh|telnet [flags]")
+	}
+	switch args[0] {
+	case "http":
+		return serveHTTP(global, args[1:], false)
+	case "overlay":
+		return serveHTTP(global, args[1:], true)
+	case "ssh":
+		return serveSSH(global, args[1:])
+	case "telnet":
+		return serveTelnet(global, args[1:])
+	}
+	return fmt.Errorf("unknown server %q (want http, overlay, ssh or telnet)", args[0])
+}
+
+//------------------------------------------------------------------------------
+// HTTP render service
+//------------------------------------------------------------------------------
+
+const maxServeText = 64 // runes; keeps a single request cheap
+
+// renderServer answers /render. Startup settings are the defaults; query
+// parameters use the same keys as every other settings layer.
+type renderServer struct {
+	defaults settings
+	limit    *rateLimiter
+	slots    chan struct{} // bounds concurrent renders
+	live     *liveBanner   // shared animated banner for /ws
+	token    string        // needed to change the live banner, if set
+	upgrader websocket.Upgrader
+}
+
+// serveHTTP runs the HTTP server; overlay also puts the overlay page at /.
+func serveHTTP(global cliFlags, args []string, overlay bool) error {
+	name, defaultAddr := "serve http", ":8080"
+	if overlay {
+		name, defaultAddr = "serve overlay", ":8090"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags := global
+	flags.register(fs)
+	addr := fs.String("addr", defaultAddr, "listen address")
+	rate := fs.Float64("rate", 5, "requests per second allowed per client")
+	burst := fs.Int("burst", 10, "requests a client may make at once")
+	token := fs.String("token", os.Getenv(envPrefix+"TOKEN"), "bearer token enabling POST /text and /theme, and needed for /ws updates")
+	anyOrigin := fs.Bool("any-origin", false, "let pages on any site open /ws, not just those served from this host")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := envFlags(fs); err != nil {
+		return err
+	}
+	s, err := loadSettings(flags)
+	if err != nil {
+		return err
+	}
+
+	live, err := newLiveBanner(s)
+	if err != nil {
+		return err
+	}
+	if live.back, err = loadBackdrop(flags); err != nil {
+		return err
+	}
+	go live.run(context.Background())
+
+	srv := &renderServer{
+		defaults: s,
+		limit:    newRateLimiter(*rate, *burst),
+		slots:    make(chan struct{}, runtime.NumCPU()),
+		live:     live,
+		token:    *token,
+	}
+	if *anyOrigin {
+		// The default compares Origin with Host, so other sites' pages
+		// can't drive the banner from a visitor's browser.
+		srv.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/render", srv.limit.wrap(http.HandlerFunc(srv.render)))
+	mux.HandleFunc("/ws", srv.serveWS)
+	mux.HandleFunc("/overlay", serveOverlay)
+	mux.HandleFunc("/metrics", serveMetrics)
+	if overlay {
+		mux.HandleFunc("/{$}", serveOverlay)
+	}
+	if *token != "" {
+		mux.Handle("/text", srv.limit.wrap(authorized(*token, srv.postText)))
+		mux.Handle("/theme", srv.limit.wrap(authorized(*token, srv.postTheme)))
+	}
+	log.Printf("serving on %s", *addr)
+	return (&http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}).ListenAndServe()
+}
+
+// render handles GET /render?text=...&font=...&format=ansi|html|svg|json.
+// hue sets the hue shift in degrees, as for the render subcommand.
+func (srv *renderServer) render(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s := srv.defaults
+	if err := applyLayer(&s, "query", func(key string) (string, bool) {
+		v, ok := q[key]
+		if !ok || len(v) == 0 {
+			return "", false
+		}
+		return v[0], true
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := checkServeText(s.Text); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var hue float64
+	if v := q.Get("hue"); v != "" {
+		h, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "hue: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		hue = h
+	}
+
+	select {
+	case srv.slots <- struct{}{}:
+		defer func() { <-srv.slots }()
+	case <-r.Context().Done():
+		return
+	}
+	start := time.Now()
+	f, err := banner.Render(s.Text, banner.WithOptions(s.options(hue)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	metrics.rendered("http", s.Font, time.Since(start))
+
+	switch format := q.Get("format"); format {
+	case "", "ansi":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, f.ANSI())
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, f.HTML())
+	case "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		fmt.Fprint(w, f.SVG())
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(f)
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q (want ansi, html, svg or json)", format), http.StatusBadRequest)
+	}
+}
+
+// checkServeText accepts short printable ASCII, which is all FIGlet fonts
+// can draw.
+func checkServeText(text string) error {
+	if len(text) > maxServeText {
+		return fmt.Errorf("text longer than %d characters", maxServeText)
+	}
+	for _, r := range text {
+		if r < ' ' || r > '~' {
+			return fmt.Errorf("text may only contain printable ASCII, got %q", r)
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+// Rate limiting
+//------------------------------------------------------------------------------
+
+// rateLimiter is a token bucket per client IP.
+type rateLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(max(1, burst)), buckets: map[string]*bucket{}}
+}
+
+// allow spends a token for client, refilling its bucket for the time since
+// its last request.
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) > 10000 {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune forgets clients whose buckets have refilled, so the map stays
+// bounded.
+func (l *rateLimiter) prune(now time.Time) {
+	for k, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func (l *rateLimiter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if l.rate > 0 && !l.allow(client, time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
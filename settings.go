@@ -0,0 +1,178 @@
+package main
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+	"glamdm/pkg/viewer"
+)
+
+//------------------------------------------------------------------------------
+// Settings snapshot
+//------------------------------------------------------------------------------
+
+// settings is everything needed to reproduce a look. Field tags are kept
+// short so the shared string stays pasteable.
+type settings struct {
+	Text      string        `json:"t"`
+	Font      string        `json:"f"`
+	Start     string        `json:"s"`
+	End       string        `json:"e"`
+	Stops     string        `json:"g,omitempty"` // colors between, as for banner.ParseStops
+	Mode      string        `json:"m"`           // render mode name
+	Animate   bool          `json:"a"`
+	StepDeg   float64       `json:"d"`
+	Interval  time.Duration `json:"i"`
+	Normalize string        `json:"n"`           // Unicode form name
+	Words     string        `json:"w,omitempty"` // word colors, as for banner.ParseWordColors
+	Sort      string        `json:"o,omitempty"` // font list order; "" is the default
+}
+
+func defaultSettings() settings {
+	return settings{
+		Text:      "glam dm",
+		Font:      banner.BuiltinFonts()[0],
+		Start:     "#8A2BE2",
+		End:       "#00FFFF",
+		Mode:      banner.ModeGlyph.Name(), // default: keep original glyphs
+		Animate:   true,
+		StepDeg:   3,                     // degrees per tick
+		Interval:  60 * time.Millisecond, // ~16 FPS
+		Normalize: banner.NFC.String(),
+		Sort:      viewer.FontOrders[0],
+	}
+}
+
+// validate reports the first setting that the model could not apply.
+func (s settings) validate() error {
+	if fontIndex(s.Font) < 0 {
+		return fmt.Errorf("unknown font %q", s.Font)
+	}
+	if _, ok := banner.ParseHex(s.Start); !ok {
+		return fmt.Errorf("invalid start color %q", s.Start)
+	}
+	if _, ok := banner.ParseHex(s.End); !ok {
+		return fmt.Errorf("invalid end color %q", s.End)
+	}
+	if _, err := banner.ParseStops(s.Stops); err != nil {
+		return err
+	}
+	if _, err := banner.ParseMode(s.Mode); err != nil {
+		return err
+	}
+	if s.StepDeg < 0.5 || s.StepDeg > 30 {
+		return fmt.Errorf("step %.1f out of range (0.5-30)", s.StepDeg)
+	}
+	if s.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if _, err := banner.ParseNormalization(s.Normalize); err != nil {
+		return err
+	}
+	if _, err := banner.ParseWordColors(s.Words); err != nil {
+		return err
+	}
+	if s.Sort != "" && !slices.Contains(viewer.FontOrders, s.Sort) {
+		return fmt.Errorf("unknown font order %q", s.Sort)
+	}
+	return nil
+}
+
+func fontIndex(name string) int {
+	for i, f := range banner.FontNames() {
+		if f == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// settings snapshots the model's current state.
+func (m model) settings() settings {
+	start, end := m.v.Colors()
+	return settings{
+		Text:      m.v.Text(),
+		Font:      m.v.Font(),
+		Start:     start,
+		End:       end,
+		Stops:     banner.FormatStops(m.v.Stops()),
+		Mode:      m.v.Mode().Name(),
+		Animate:   m.v.Animating(),
+		StepDeg:   m.v.Step(),
+		Interval:  m.v.Interval(),
+		Normalize: m.v.Normalization().String(),
+		Words:     banner.FormatWordColors(m.v.WordColors()),
+		Sort:      m.v.FontOrder(),
+	}
+}
+
+// applySettings loads s into the viewer. s should already be validated. The
+// returned command restarts the animation if s turned it on.
+func (m *model) applySettings(s settings) tea.Cmd {
+	m.v.SetText(s.Text)
+	_ = m.v.SetFont(s.Font)
+	_ = m.v.SetColors(s.Start, s.End)
+	if stops, err := banner.ParseStops(s.Stops); err == nil {
+		m.v.SetStops(stops)
+	}
+	if mode, err := banner.ParseMode(s.Mode); err == nil {
+		m.v.SetMode(mode)
+	}
+	m.v.SetStep(s.StepDeg)
+	m.v.SetInterval(s.Interval)
+	if n, err := banner.ParseNormalization(s.Normalize); err == nil {
+		m.v.SetNormalization(n)
+	}
+	if words, err := banner.ParseWordColors(s.Words); err == nil {
+		m.v.SetWordColors(words)
+	}
+	if order := cmp.Or(s.Sort, viewer.FontOrders[0]); order != m.v.FontOrder() {
+		_ = m.v.SetFontOrder(order)
+	}
+	return m.v.SetAnimate(s.Animate)
+}
+
+// options converts s for the banner package; s should already be validated.
+func (s settings) options(hueShift float64) banner.Options {
+	start, _ := banner.ParseHex(s.Start)
+	end, _ := banner.ParseHex(s.End)
+	stops, _ := banner.ParseStops(s.Stops)
+	mode, _ := banner.ParseMode(s.Mode)
+	n, _ := banner.ParseNormalization(s.Normalize)
+	words, _ := banner.ParseWordColors(s.Words)
+	return banner.Options{Font: s.Font, Start: start, End: end, Stops: stops, Mode: mode, HueShift: hueShift, Normalize: n, Words: words}
+}
+
+//------------------------------------------------------------------------------
+// Share strings
+//------------------------------------------------------------------------------
+
+// encodeSettings packs s into a URL-safe base64 token.
+func encodeSettings(s settings) string {
+	b, _ := json.Marshal(s)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeSettings unpacks a token from encodeSettings. Fields missing from
+// the token keep their defaults.
+func decodeSettings(token string) (settings, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return settings{}, fmt.Errorf("decode share string: %w", err)
+	}
+	s := defaultSettings()
+	if err := json.Unmarshal(b, &s); err != nil {
+		return settings{}, fmt.Errorf("decode share string: %w", err)
+	}
+	if err := s.validate(); err != nil {
+		return settings{}, fmt.Errorf("share string: %w", err)
+	}
+	return s, nil
+}
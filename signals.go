@@ -0,0 +1,44 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+//------------------------------------------------------------------------------
+// Signal controls (SIGUSR1 font, SIGUSR2 animation, SIGHUP reload)
+//------------------------------------------------------------------------------
+
+// signalMsg is a control signal, translated to something Update can act on.
+type signalMsg int
+
+const (
+	signalNextFont signalMsg = iota
+	signalToggleAnimate
+	signalReload
+)
+
+func (m model) handleSignal(msg signalMsg) (model, tea.Cmd) {
+	switch msg {
+	case signalNextFont:
+		fonts := m.v.Fonts()
+		for i, f := range fonts {
+			if f == m.v.Font() {
+				_ = m.v.SetFont(fonts[(i+1)%len(fonts)])
+				break
+			}
+		}
+		m.status = "font: " + m.v.Font()
+		return m, nil
+	case signalToggleAnimate:
+		return m, m.v.SetAnimate(!m.v.Animating())
+	case signalReload:
+		// Unlike the file watcher, an explicit reload applies everything.
+		s, err := loadSettings(m.flags)
+		if err != nil {
+			m.status = "config: " + err.Error()
+			return m, nil
+		}
+		m.loaded, m.preset = s, s
+		m.status = "config reloaded"
+		return m, m.applySettings(s)
+	}
+	return m, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// notifySignals is a no-op where SIGUSR1/SIGUSR2/SIGHUP don't exist; use
+// the control socket instead.
+func notifySignals(*tea.Program) func() { return func() {} }
@@ -0,0 +1,40 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notifySignals forwards SIGUSR1 (next font), SIGUSR2 (toggle animation)
+// and SIGHUP (reload config) to p. The returned func stops forwarding.
+func notifySignals(p *tea.Program) func() {
+	ch := make(chan os.Signal, 4)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				switch sig {
+				case syscall.SIGUSR1:
+					p.Send(signalMsg(signalNextFont))
+				case syscall.SIGUSR2:
+					p.Send(signalMsg(signalToggleAnimate))
+				case syscall.SIGHUP:
+					p.Send(signalMsg(signalReload))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
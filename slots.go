@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Preset slots (alt+1-9 load, alt+shift+1-9 save)
+//------------------------------------------------------------------------------
+
+// Digits alone would be typed into the text, so the slot keys take alt.
+
+const (
+	slotsFileName = "slots.json"
+	slotCount     = 9
+	slotSaveKeys  = "!@#$%^&*(" // shifted 1-9 on a US layout
+)
+
+// slots are looks kept for quick A/B comparison; nil slots are empty.
+type slots [slotCount]*settings
+
+func slotsPath() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, slotsFileName)
+	}
+	return ""
+}
+
+// loadSlots reads the saved slots. A missing or damaged file leaves them
+// empty, as do slots that no longer validate.
+func loadSlots(path string) slots {
+	var s slots
+	if path == "" {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &s) != nil {
+		return slots{}
+	}
+	for i, v := range s {
+		if v != nil && v.validate() != nil {
+			s[i] = nil
+		}
+	}
+	return s
+}
+
+func saveSlots(path string, s slots) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, _ := json.Marshal(s)
+	return os.WriteFile(path, data, 0o644)
+}
+
+// slotKey is the slot, from 0, that an alt+digit or alt+shifted digit
+// key names, and whether it saves.
+func slotKey(key string) (slot int, save bool) {
+	k := strings.TrimPrefix(key, "alt+")
+	if k >= "1" && k <= "9" {
+		return int(k[0] - '1'), false
+	}
+	return strings.Index(slotSaveKeys, k), true
+}
+
+// loadSlot applies slot i.
+func (m *model) loadSlot(i int) tea.Cmd {
+	s := m.slots[i]
+	if s == nil {
+		m.status = fmt.Sprintf("slot %d is empty (alt+shift+%d saves)", i+1, i+1)
+		return nil
+	}
+	m.status = fmt.Sprintf("slot %d loaded", i+1)
+	if m.slotsFile != "" {
+		m.preset = *s
+	}
+	return m.applySettings(*s)
+}
+
+// saveSlot keeps the current look in slot i, persisting the slots unless
+// the session is remote.
+func (m *model) saveSlot(i int) tea.Cmd {
+	s := m.settings()
+	m.slots[i] = &s
+	m.status = fmt.Sprintf("saved slot %d", i+1)
+	path, all := m.slotsFile, m.slots
+	if path == "" {
+		return nil
+	}
+	m.preset = s
+	return func() tea.Msg {
+		if err := saveSlots(path, all); err != nil {
+			return statusMsg("slots: " + err.Error())
+		}
+		return nil
+	}
+}
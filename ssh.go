@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+)
+
+//------------------------------------------------------------------------------
+// SSH server (serve ssh)
+//------------------------------------------------------------------------------
+
+const hostKeyName = "ssh_host_ed25519"
+
+// serveSSH runs the TUI for every SSH session. Sessions are public: they
+// start from the server's settings, keep history in memory, and cannot
+// write to the server's config directory.
+func serveSSH(global cliFlags, args []string) error {
+	fs := flag.NewFlagSet("serve ssh", flag.ContinueOnError)
+	flags := global
+	flags.register(fs)
+	addr := fs.String("addr", ":2222", "listen address")
+	defaultKey := ""
+	if dir := configDir(); dir != "" {
+		defaultKey = filepath.Join(dir, hostKeyName)
+	}
+	hostKey := fs.String("host-key", defaultKey, "host key file, created if missing; needed when there is no config dir")
+	idle := fs.Duration("idle", 10*time.Minute, "disconnect sessions idle this long")
+	metricsAddr := fs.String("metrics", "", "serve /metrics on this address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := envFlags(fs); err != nil {
+		return err
+	}
+	if *hostKey == "" {
+		return errors.New("no config directory to keep the host key in; give --host-key FILE")
+	}
+	s, err := loadSettings(flags)
+	if err != nil {
+		return err
+	}
+
+	srv, err := wish.NewServer(
+		wish.WithAddress(*addr),
+		wish.WithHostKeyPath(*hostKey),
+		wish.WithIdleTimeout(*idle),
+		wish.WithMiddleware(
+			bubbletea.Middleware(func(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
+				return newSessionModel(sess, s), []tea.ProgramOption{tea.WithAltScreen()}
+			}),
+			activeterm.Middleware(), // the TUI needs a PTY
+			countSessions,
+			logging.Middleware(),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	listenMetrics(*metricsAddr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("serving ssh on %s", *addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			errc <- err
+		}
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdown)
+}
+
+// countSessions tracks open sessions for /metrics.
+func countSessions(next ssh.Handler) ssh.Handler {
+	return func(sess ssh.Session) {
+		defer metrics.session("ssh")()
+		next(sess)
+	}
+}
+
+// newSessionModel builds the app for one SSH session, styled for the
+// client's terminal.
+func newSessionModel(sess ssh.Session, s settings) model {
+	r := bubbletea.MakeRenderer(sess)
+	pty, _, ok := sess.Pty()
+	env := func(key string) string {
+		if key == "TERM" {
+			return pty.Term
+		}
+		for _, kv := range sess.Environ() {
+			if k, v, _ := strings.Cut(kv, "="); k == key {
+				return v
+			}
+		}
+		return ""
+	}
+	note := detectCaps(env, r.ColorProfile()).adjust(&s)
+
+	m := newModel(s)
+	m.public = true
+	m.status = note
+	m.v.SetRenderer(r)
+	if ok {
+		m.w, m.h = pty.Window.Width, pty.Window.Height
+	}
+	return m
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// System info feed (--sysinfo[=FIELDS])
+//------------------------------------------------------------------------------
+
+// sysinfoRotate is how long each field is shown before the next.
+const sysinfoRotate = 5 * time.Second
+
+const defaultSysinfoFields = "host,uptime,load,ip"
+
+// sysinfoFields read one piece of system info each, as short banner text.
+var sysinfoFields = map[string]func() (string, error){
+	"host":   os.Hostname,
+	"uptime": uptime,
+	"load":   loadAverage,
+	"ip":     localIP,
+}
+
+// sysinfoFeed cycles through system info fields, a neofetch-style greeter.
+type sysinfoFeed struct {
+	fields []string
+	start  time.Time
+}
+
+// newSysinfoFeed parses a comma-separated field list.
+func newSysinfoFeed(list string) (sysinfoFeed, error) {
+	f := sysinfoFeed{start: time.Now()}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := sysinfoFields[name]; !ok {
+			return f, fmt.Errorf("--sysinfo: unknown field %q (want %s)", name, defaultSysinfoFields)
+		}
+		f.fields = append(f.fields, name)
+	}
+	return f, nil
+}
+
+func (sysinfoFeed) name() string { return "sysinfo" }
+
+func (s sysinfoFeed) text(now time.Time) (string, error) {
+	name := s.fields[int(now.Sub(s.start)/sysinfoRotate)%len(s.fields)]
+	v, err := sysinfoFields[name]()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
+
+func (s sysinfoFeed) next(now time.Time) time.Time {
+	return s.start.Add(now.Sub(s.start).Truncate(sysinfoRotate) + sysinfoRotate)
+}
+
+var errNotLinux = errors.New("only available on Linux")
+
+// uptime reads /proc/uptime, as "up 3d 4h" or "up 5h 12m".
+func uptime() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errNotLinux
+	}
+	b, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return "", err
+	}
+	secs, err := strconv.ParseFloat(strings.Fields(string(b))[0], 64)
+	if err != nil {
+		return "", err
+	}
+	d := time.Duration(secs) * time.Second
+	days, hours, mins := int(d.Hours())/24, int(d.Hours())%24, int(d.Minutes())%60
+	if days > 0 {
+		return fmt.Sprintf("up %dd %dh", days, hours), nil
+	}
+	return fmt.Sprintf("up %dh %dm", hours, mins), nil
+}
+
+// loadAverage reads the 1-minute load average from /proc/loadavg.
+func loadAverage() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errNotLinux
+	}
+	b, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return "", err
+	}
+	return "load " + strings.Fields(string(b))[0], nil
+}
+
+// localIP is the first IPv4 address that isn't loopback.
+func localIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, a := range addrs {
+		if ip, ok := a.(*net.IPNet); ok && !ip.IP.IsLoopback() && ip.IP.To4() != nil {
+			return ip.IP.String(), nil
+		}
+	}
+	return "", errors.New("no network address")
+}
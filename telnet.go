@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Telnet server (serve telnet)
+//------------------------------------------------------------------------------
+
+// Telnet protocol bytes (RFC 854, 1091 TTYPE, 1073 NAWS).
+const (
+	telIAC  = 255
+	telDONT = 254
+	telDO   = 253
+	telWONT = 252
+	telWILL = 251
+	telSB   = 250
+	telSE   = 240
+
+	optEcho  = 1
+	optSGA   = 3
+	optTTYPE = 24
+	optNAWS  = 31
+
+	ttypeIS   = 0
+	ttypeSEND = 1
+)
+
+// negotiateWait bounds how long a client gets to report its terminal.
+const negotiateWait = 2 * time.Second
+
+// writeWait is how long a client may go without taking a frame before it
+// is dropped, so clients that never read don't hold a slot forever.
+const writeWait = 10 * time.Second
+
+// The largest window a client may report; every frame is placed on a
+// canvas that size. maxSubneg bounds a subnegotiation body.
+const (
+	maxTelnetWidth  = 500
+	maxTelnetHeight = 200
+	maxSubneg       = 256
+)
+
+// serveTelnet streams the animated banner, read-only, to every client.
+// Each connection gets colors for the terminal type it reports.
+func serveTelnet(global cliFlags, args []string) error {
+	fs := flag.NewFlagSet("serve telnet", flag.ContinueOnError)
+	flags := global
+	flags.register(fs)
+	addr := fs.String("addr", ":2323", "listen address")
+	fps := fs.Float64("fps", 10, "frames per second")
+	maxConns := fs.Int("max", 64, "maximum simultaneous clients")
+	metricsAddr := fs.String("metrics", "", "serve /metrics on this address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := envFlags(fs); err != nil {
+		return err
+	}
+	s, err := loadSettings(flags)
+	if err != nil {
+		return err
+	}
+	opts := banner.WithOptions(s.options(0))
+	art, err := banner.Compose(s.Text, opts)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	listenMetrics(*metricsAddr)
+	log.Printf("serving telnet on %s", *addr)
+	slots := make(chan struct{}, max(1, *maxConns))
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			io.WriteString(conn, "Too many viewers, try again later.\r\n")
+			conn.Close()
+			continue
+		}
+		go func() {
+			defer func() { <-slots }()
+			defer conn.Close()
+			streamTelnet(ctx, conn, art, opts, s.StepDeg, *fps)
+		}()
+	}
+}
+
+// streamTelnet plays the banner to one client until it sends q, hangs up,
+// or the server stops.
+func streamTelnet(ctx context.Context, conn net.Conn, art banner.Art, opts banner.Option, step, fps float64) {
+	defer metrics.session("telnet")()
+	cl := negotiate(conn)
+	log.Printf("telnet %s: term %q, %dx%d", conn.RemoteAddr(), cl.term, cl.width, cl.height)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		// Read-only: input only matters for hanging up.
+		defer cancel()
+		for {
+			b, err := cl.r.ReadByte()
+			if err != nil || b == 'q' || b == 3 { // q or ctrl+c
+				return
+			}
+			if b == telIAC {
+				skipCommand(cl.r)
+			}
+		}
+	}()
+
+	aw := banner.NewAnimationWriter(crlfWriter{deadlineWriter{conn}}, art, opts, banner.WithEffect(banner.NewHueCycle(step)))
+	aw.FPS = fps
+	aw.Width, aw.Height = cl.width, cl.height
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(profileForTerm(cl.term))
+	aw.Renderer = r
+	_ = aw.Run(ctx)
+}
+
+// telnetClient is what negotiation learned about a connection.
+type telnetClient struct {
+	r             *bufio.Reader
+	term          string
+	width, height int
+}
+
+// negotiate puts the client in character mode with local echo off,
+// then asks for its terminal type and window size. Clients that ignore
+// the options are treated as plain ANSI terminals.
+func negotiate(conn net.Conn) telnetClient {
+	cl := telnetClient{r: bufio.NewReader(conn)}
+	conn.Write([]byte{
+		telIAC, telWILL, optEcho,
+		telIAC, telWILL, optSGA,
+		telIAC, telDO, optTTYPE,
+		telIAC, telDO, optNAWS,
+	})
+
+	conn.SetReadDeadline(time.Now().Add(negotiateWait))
+	defer conn.SetReadDeadline(time.Time{})
+	typed := false
+	for !typed {
+		b, err := cl.r.ReadByte()
+		if err != nil {
+			break // timed out: take what we have
+		}
+		if b != telIAC {
+			continue
+		}
+		cmd, err := cl.r.ReadByte()
+		if err != nil {
+			break
+		}
+		switch cmd {
+		case telWILL:
+			if opt, _ := cl.r.ReadByte(); opt == optTTYPE {
+				conn.Write([]byte{telIAC, telSB, optTTYPE, ttypeSEND, telIAC, telSE})
+			}
+		case telWONT:
+			if opt, _ := cl.r.ReadByte(); opt == optTTYPE {
+				typed = true
+			}
+		case telDO, telDONT:
+			cl.r.ReadByte()
+		case telSB:
+			body := readSubneg(cl.r)
+			switch {
+			case len(body) >= 2 && body[0] == optTTYPE && body[1] == ttypeIS:
+				cl.term = strings.ToLower(string(body[2:]))
+				typed = true
+			case len(body) == 5 && body[0] == optNAWS:
+				cl.width = min(int(body[1])<<8|int(body[2]), maxTelnetWidth)
+				cl.height = min(int(body[3])<<8|int(body[4]), maxTelnetHeight)
+			}
+		}
+	}
+	return cl
+}
+
+// skipCommand discards the rest of a command after IAC, so option bytes
+// are not mistaken for keys.
+func skipCommand(r *bufio.Reader) {
+	switch cmd, _ := r.ReadByte(); cmd {
+	case telWILL, telWONT, telDO, telDONT:
+		r.ReadByte()
+	case telSB:
+		readSubneg(r)
+	}
+}
+
+// readSubneg reads a subnegotiation body up to IAC SE, undoubling IAC IAC.
+// Bytes past maxSubneg are dropped.
+func readSubneg(r *bufio.Reader) []byte {
+	var body []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return body
+		}
+		if b == telIAC {
+			next, err := r.ReadByte()
+			if err != nil || next == telSE {
+				return body
+			}
+			b = next
+		}
+		if len(body) < maxSubneg {
+			body = append(body, b)
+		}
+	}
+}
+
+// profileForTerm maps a reported terminal type to a color depth.
+func profileForTerm(term string) termenv.Profile {
+	switch {
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "direct"),
+		strings.Contains(term, "kitty"), strings.Contains(term, "ghostty"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256"):
+		return termenv.ANSI256
+	case term == "dumb", term == "unknown":
+		return termenv.Ascii
+	}
+	return termenv.ANSI // including "", "ansi", "vt100" and plain "xterm"
+}
+
+// crlfWriter turns bare LF into CRLF, as the network virtual terminal
+// expects.
+type crlfWriter struct{ w io.Writer }
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// deadlineWriter gives every write to conn writeWait to finish.
+type deadlineWriter struct{ conn net.Conn }
+
+func (d deadlineWriter) Write(p []byte) (int, error) {
+	d.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return d.conn.Write(p)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestNegotiateWindowSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		naws          []byte
+		width, height int
+	}{
+		{"reported", []byte{0, 120, 0, 40}, 120, 40},
+		{"too big", []byte{telIAC, telIAC, telIAC, telIAC, telIAC, telIAC, telIAC, telIAC}, maxTelnetWidth, maxTelnetHeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer server.Close()
+			defer client.Close()
+			go io.Copy(io.Discard, client)
+			go func() {
+				msg := append([]byte{telIAC, telSB, optNAWS}, tt.naws...)
+				msg = append(msg, telIAC, telSE, telIAC, telWONT, optTTYPE)
+				client.Write(msg)
+			}()
+			cl := negotiate(server)
+			if cl.width != tt.width || cl.height != tt.height {
+				t.Errorf("window %dx%d, want %dx%d", cl.width, cl.height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestReadSubnegBounded(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	go func() {
+		client.Write(make([]byte, 10*maxSubneg))
+		client.Write([]byte{telIAC, telSE})
+		client.Close()
+	}()
+	if body := readSubneg(bufio.NewReader(server)); len(body) != maxSubneg {
+		t.Errorf("body of %d bytes, want %d", len(body), maxSubneg)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"glamdm/pkg/banner"
+)
+
+//------------------------------------------------------------------------------
+// Themes
+//------------------------------------------------------------------------------
+
+// A theme is a config file without text: it describes a look that can be
+// applied to any banner. Themes live in <config>/themes/<name>.toml.
+
+const (
+	themesDirName = "themes"
+	themeExt      = ".toml"
+)
+
+type themeFile struct {
+	Font      string  `toml:"font"`
+	Start     string  `toml:"start"`
+	End       string  `toml:"end"`
+	Stops     string  `toml:"stops,omitempty"`
+	Mode      string  `toml:"mode"`
+	Animate   bool    `toml:"animate"`
+	Step      float64 `toml:"step"`
+	Interval  string  `toml:"interval"`
+	Normalize string  `toml:"normalize,omitempty"`
+	Words     string  `toml:"words,omitempty"`
+	Sort      string  `toml:"sort,omitempty"`
+}
+
+func themesDir() string {
+	if dir := configDir(); dir != "" {
+		return filepath.Join(dir, themesDirName)
+	}
+	return ""
+}
+
+func themePath(name string) (string, error) {
+	if !validName(name) {
+		return "", fmt.Errorf("invalid theme name %q", name)
+	}
+	dir := themesDir()
+	if dir == "" {
+		return "", errors.New("no config directory")
+	}
+	return filepath.Join(dir, name+themeExt), nil
+}
+
+// validName rejects names that would escape their directory.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// applyTheme overlays a saved theme onto s.
+func applyTheme(s *settings, name string) error {
+	p, err := themePath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(p); err != nil {
+		return fmt.Errorf("theme %q not found", name)
+	}
+	text := s.Text
+	if err := applyFile(s, p); err != nil {
+		return err
+	}
+	s.Text = text
+	return nil
+}
+
+func encodeTheme(s settings) []byte {
+	var b bytes.Buffer
+	_ = toml.NewEncoder(&b).Encode(themeFile{
+		Font:      s.Font,
+		Start:     s.Start,
+		End:       s.End,
+		Stops:     s.Stops,
+		Mode:      s.Mode,
+		Animate:   s.Animate,
+		Step:      s.StepDeg,
+		Interval:  s.Interval.String(),
+		Normalize: s.Normalize,
+		Words:     s.Words,
+		Sort:      s.Sort,
+	})
+	return b.Bytes()
+}
+
+func saveTheme(name string, s settings) error {
+	p, err := themePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, encodeTheme(s), 0o644)
+}
+
+// cmdTheme implements ":theme NAME" and ":theme save NAME".
+func cmdTheme(m *model, args []string) (string, tea.Cmd) {
+	switch {
+	case len(args) == 2 && args[0] == "save":
+		if m.public {
+			return "theme: saving is disabled on this server", nil
+		}
+		if err := saveTheme(args[1], m.settings()); err != nil {
+			return "theme: " + err.Error(), nil
+		}
+		m.preset = m.settings()
+		if m.quitAfterSave {
+			return "saved theme " + args[1], tea.Quit
+		}
+		return "saved theme " + args[1], nil
+	case len(args) == 1:
+		s := m.settings()
+		if err := applyTheme(&s, args[0]); err != nil {
+			return "theme: " + err.Error(), nil
+		}
+		if err := s.validate(); err != nil {
+			return "theme: " + err.Error(), nil
+		}
+		m.preset = s
+		return "applied theme " + args[0], m.applySettings(s)
+	}
+	return "usage: theme NAME | theme save NAME", nil
+}
+
+//------------------------------------------------------------------------------
+// Theme bundles (theme export / theme import)
+//------------------------------------------------------------------------------
+
+// A bundle is a tar archive holding themes/<name>.toml and, when the theme
+// uses a custom font, fonts/<font>.flf.
+
+func exportTheme(name, out string) error {
+	p, err := themePath(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("theme %q not found", name)
+	}
+	var s settings
+	if err := applyFile(&s, p); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	add := func(name string, body []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(body)
+		return err
+	}
+	if err := add(path.Join(themesDirName, name+themeExt), data); err != nil {
+		return err
+	}
+	if fp, ok := banner.FontPath(s.Font); ok {
+		font, err := os.ReadFile(fp)
+		if err != nil {
+			return err
+		}
+		if err := add(path.Join(fontsDirName, s.Font+fontExt), font); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(out, buf.Bytes(), 0o644)
+}
+
+// importTheme unpacks a bundle into the config dir and returns the installed
+// file names. Only themes/*.toml and fonts/*.flf entries are accepted.
+func importTheme(in string) ([]string, error) {
+	root := configDir()
+	if root == "" {
+		return nil, errors.New("no config directory")
+	}
+	f, err := os.Open(in)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var installed []string
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return installed, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dir, file := path.Split(path.Clean(hdr.Name))
+		base := strings.TrimSuffix(file, path.Ext(file))
+		switch {
+		case dir == themesDirName+"/" && path.Ext(file) == themeExt && validName(base):
+		case dir == fontsDirName+"/" && path.Ext(file) == fontExt && validName(base):
+		default:
+			return installed, fmt.Errorf("unexpected entry %q in bundle", hdr.Name)
+		}
+		dst := filepath.Join(root, filepath.FromSlash(dir), file)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return installed, err
+		}
+		body, err := io.ReadAll(io.LimitReader(tr, 4<<20))
+		if err != nil {
+			return installed, err
+		}
+		if err := os.WriteFile(dst, body, 0o644); err != nil {
+			return installed, err
+		}
+		installed = append(installed, path.Join(dir, file))
+	}
+	return installed, nil
+}
+
+// runThemeCommand implements the "theme" subcommand.
+func runThemeCommand(args []string) error {
+	const usage = "usage: theme export NAME[.tar] [FILE] | theme import FILE"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "export":
+		if len(args) < 2 || len(args) > 3 {
+			return errors.New(usage)
+		}
+		name := strings.TrimSuffix(filepath.Base(args[1]), ".tar")
+		out := name + ".tar"
+		if strings.HasSuffix(args[1], ".tar") {
+			out = args[1]
+		}
+		if len(args) == 3 {
+			out = args[2]
+		}
+		if err := exportTheme(name, out); err != nil {
+			return err
+		}
+		fmt.Println("wrote", out)
+	case "import":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+		installed, err := importTheme(args[1])
+		for _, f := range installed {
+			fmt.Println("installed", f)
+		}
+		return err
+	default:
+		return errors.New(usage)
+	}
+	return nil
+}
+
+// copyColors freezes the animation on the colors shown, for saving as a
+// theme, and copies them to the terminal's clipboard with OSC 52.
+func (m *model) copyColors() {
+	start, end := m.v.FreezeColors()
+	m.v.Renderer().Output().Copy(start + " " + end)
+	m.status = fmt.Sprintf("colors frozen at %s %s and copied (:theme save NAME keeps them)", start, end)
+}
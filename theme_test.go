@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestThemeRoundTrip saves a theme, exports and reimports it, and applies
+// it, checking every setting but the text survives.
+func TestThemeRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s := defaultSettings()
+	s.Font, s.Start, s.End, s.Stops = "slant", "#112233", "#445566", "#ff0000@50"
+	s.Mode, s.Animate, s.StepDeg = "block", false, 12
+	s.Normalize, s.Words, s.Sort = "strip", "FAILED=#ff0000", "name"
+	if err := saveTheme("look", s); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "look.tar")
+	if err := exportTheme("look", bundle); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := themePath("look")
+	if err := os.Remove(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importTheme(bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	got := defaultSettings()
+	got.Text = "kept"
+	if err := applyTheme(&got, "look"); err != nil {
+		t.Fatal(err)
+	}
+	want := s
+	want.Text = "kept"
+	if got != want {
+		t.Errorf("theme applied as\n%+v\nwant\n%+v", got, want)
+	}
+}
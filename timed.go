@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Timed exit (--duration, --loops)
+//------------------------------------------------------------------------------
+
+// exitMsg quits the TUI once a --duration or --loops run is up.
+type exitMsg struct{}
+
+// loopFrames is how many frames one loop takes: a full hue cycle of step
+// degrees a frame, or one pass through the --play sequence.
+func loopFrames(step float64, seq sequence) int {
+	if len(seq.frames) > 0 {
+		return len(seq.frames) * seq.hold
+	}
+	return int(math.Ceil(360 / step))
+}
+
+// limitFrames cuts a render of frames at fps short for --loops and
+// --duration, whichever ends first. 0 frames, for no limit, stays 0 unless
+// either is given.
+func (c cliFlags) limitFrames(frames int, step float64, seq sequence, fps float64) int {
+	limit := func(n int) {
+		if frames == 0 || n < frames {
+			frames = n
+		}
+	}
+	if c.loops > 0 {
+		limit(c.loops * loopFrames(step, seq))
+	}
+	if c.duration > 0 {
+		if fps <= 0 {
+			fps = 15
+		}
+		limit(max(1, int(math.Ceil(c.duration.Seconds()*fps))))
+	}
+	return frames
+}
+
+// runLimit is how long the TUI runs for --loops and --duration, whichever
+// ends first, or 0 for no limit. Loops are hue cycles at the starting
+// speed, or passes through played, a --play sequence shown every so often.
+func (c cliFlags) runLimit(s settings, played int, every time.Duration) time.Duration {
+	var d time.Duration
+	limit := func(l time.Duration) {
+		if d == 0 || l < d {
+			d = l
+		}
+	}
+	if c.loops > 0 {
+		loop := time.Duration(loopFrames(s.StepDeg, sequence{})) * s.Interval
+		if played > 0 {
+			loop = time.Duration(played) * every
+		}
+		limit(time.Duration(c.loops) * loop)
+	}
+	if c.duration > 0 {
+		limit(c.duration)
+	}
+	return d
+}
+
+// exitAfter quits after d, skipping the unsaved-changes question.
+func exitAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return exitMsg{} })
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+//------------------------------------------------------------------------------
+// Config hot-reload
+//------------------------------------------------------------------------------
+
+const watchInterval = 500 * time.Millisecond
+
+// configPollMsg carries the last seen modification times between polls so
+// the check itself can run off the update loop.
+type configPollMsg struct {
+	mods map[string]time.Time
+}
+
+// configReloadMsg is sent when a watched file changed.
+type configReloadMsg struct {
+	mods map[string]time.Time
+	s    settings
+	err  error
+}
+
+// watchedFiles lists the files to poll: the user config (even if it does not
+// exist yet), the project profile in effect at startup, or a new one in
+// the working directory, and the --theme file. There are none with
+// --no-config.
+func watchedFiles(flags cliFlags) []string {
+	var files []string
+	if noConfig {
+		return nil
+	}
+	if p := userConfigPath(); p != "" {
+		files = append(files, p)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if p := findProfile(wd); p != "" {
+			files = append(files, p)
+		} else {
+			files = append(files, filepath.Join(wd, profileName))
+		}
+	}
+	if flags.theme != "" {
+		if p, err := themePath(flags.theme); err == nil {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+func modTimes(files []string) map[string]time.Time {
+	mods := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil {
+			mods[f] = fi.ModTime()
+		}
+	}
+	return mods
+}
+
+func sameMods(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for f, t := range a {
+		if !b[f].Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// pollConfig waits one interval, then re-stats the watched files.
+func pollConfig(files []string, flags cliFlags, prev map[string]time.Time) tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		mods := modTimes(files)
+		if sameMods(prev, mods) {
+			return configPollMsg{mods: mods}
+		}
+		s, err := loadSettings(flags)
+		return configReloadMsg{mods: mods, s: s, err: err}
+	})
+}
+
+// mergeChanged applies to cur only the fields that differ between the
+// previously loaded settings and the newly loaded ones, so edits made in the
+// TUI survive a reload that did not touch them.
+func mergeChanged(cur, old, loaded settings) settings {
+	if loaded.Text != old.Text {
+		cur.Text = loaded.Text
+	}
+	if loaded.Font != old.Font {
+		cur.Font = loaded.Font
+	}
+	if loaded.Start != old.Start {
+		cur.Start = loaded.Start
+	}
+	if loaded.End != old.End {
+		cur.End = loaded.End
+	}
+	if loaded.Stops != old.Stops {
+		cur.Stops = loaded.Stops
+	}
+	if loaded.Mode != old.Mode {
+		cur.Mode = loaded.Mode
+	}
+	if loaded.Animate != old.Animate {
+		cur.Animate = loaded.Animate
+	}
+	if loaded.StepDeg != old.StepDeg {
+		cur.StepDeg = loaded.StepDeg
+	}
+	if loaded.Interval != old.Interval {
+		cur.Interval = loaded.Interval
+	}
+	if loaded.Normalize != old.Normalize {
+		cur.Normalize = loaded.Normalize
+	}
+	if loaded.Words != old.Words {
+		cur.Words = loaded.Words
+	}
+	if loaded.Sort != old.Sort {
+		cur.Sort = loaded.Sort
+	}
+	return cur
+}
+
+func (m model) handleConfigReload(msg configReloadMsg) (model, tea.Cmd) {
+	next := pollConfig(m.watched, m.flags, msg.mods)
+	if msg.err != nil {
+		m.status = "config: " + msg.err.Error()
+		return m, next
+	}
+	cmd := m.applySettings(mergeChanged(m.settings(), m.loaded, msg.s))
+	m.loaded, m.preset = msg.s, msg.s
+	m.status = "config reloaded"
+	return m, tea.Batch(next, cmd)
+}
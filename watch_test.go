@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestMergeChanged changes each settings field in turn in the reloaded
+// config, so a field added to settings but not to mergeChanged fails.
+func TestMergeChanged(t *testing.T) {
+	old := defaultSettings()
+	cur := old
+	cur.Text = "edited in the TUI"
+	edited := map[string]any{
+		"Text": "reloaded", "Font": "slant", "Start": "#000000", "End": "#FFFFFF",
+		"Stops": "#FF0000@50", "Mode": "block", "Animate": !old.Animate,
+		"StepDeg": old.StepDeg + 1, "Interval": old.Interval + time.Second,
+		"Normalize": "strip", "Words": "hi=#FF0000", "Sort": "name",
+	}
+	typ := reflect.TypeOf(old)
+	for i := range typ.NumField() {
+		name := typ.Field(i).Name
+		t.Run(name, func(t *testing.T) {
+			v, ok := edited[name]
+			if !ok {
+				t.Fatalf("no edited value for %s", name)
+			}
+			loaded := old
+			reflect.ValueOf(&loaded).Elem().Field(i).Set(reflect.ValueOf(v))
+			got := mergeChanged(cur, old, loaded)
+			if f := reflect.ValueOf(got).Field(i).Interface(); f != v {
+				t.Errorf("%s = %v after reloading %v", name, f, v)
+			}
+			if name != "Text" && got.Text != cur.Text {
+				t.Errorf("Text = %q, want the TUI edit kept", got.Text)
+			}
+		})
+	}
+}
+
+func TestWatchedFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	theme, err := themePath("neon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		theme string
+		want  bool // theme watched
+	}{
+		{"no theme", "", false},
+		{"theme", "neon", true},
+		{"invalid theme name", "../neon", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := watchedFiles(cliFlags{theme: tt.theme})
+			if got := slices.Contains(files, theme); got != tt.want {
+				t.Errorf("watching %q; theme file watched %v, want %v", files, got, tt.want)
+			}
+			if len(files) < 2 {
+				t.Errorf("watching %q, want the user config and project profile too", files)
+			}
+		})
+	}
+}
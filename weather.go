@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+// Weather feed (--weather LOCATION)
+//------------------------------------------------------------------------------
+
+const (
+	defaultWeatherInterval = 15 * time.Minute
+	weatherTimeout         = 10 * time.Second
+
+	wttrURL        = "https://wttr.in/"
+	openWeatherURL = "https://api.openweathermap.org/data/2.5/weather"
+)
+
+var weatherClient = &http.Client{Timeout: weatherTimeout}
+
+// weatherFeed shows the temperature and conditions at a location, from
+// OpenWeather when there is an API key and wttr.in otherwise.
+//
+// The last good reading is cached on disk. A reading younger than every is
+// reused instead of fetched, so restarts don't hammer the service, and when
+// a fetch fails the cached reading is shown with its age as the caption.
+type weatherFeed struct {
+	location string
+	key      string // OpenWeather API key
+	every    time.Duration
+	cache    string // cache file, or "" for none
+
+	stale time.Time // when the shown reading was fetched, if it is old
+}
+
+func newWeatherFeed(location, key string, every time.Duration) *weatherFeed {
+	w := &weatherFeed{location: location, key: key, every: every}
+	if dir, err := os.UserCacheDir(); err == nil {
+		w.cache = filepath.Join(dir, configDirName, "weather-"+url.QueryEscape(strings.ToLower(location)))
+	}
+	return w
+}
+
+func (*weatherFeed) name() string { return "weather" }
+
+func (w *weatherFeed) text(now time.Time) (string, error) {
+	w.stale = time.Time{}
+	cached, at, cacheErr := w.readCache()
+	if cacheErr == nil && now.Sub(at) < w.every {
+		return cached, nil
+	}
+	text, err := w.fetch()
+	if err != nil {
+		if cacheErr != nil {
+			return "", err
+		}
+		w.stale = at
+		return cached, nil
+	}
+	w.writeCache(text)
+	return text, nil
+}
+
+func (w *weatherFeed) next(now time.Time) time.Time { return now.Add(w.every) }
+
+func (w *weatherFeed) caption(time.Time) string {
+	if w.stale.IsZero() {
+		return ""
+	}
+	return "offline, as of " + w.stale.Format("Jan 2 15:04")
+}
+
+func (w *weatherFeed) fetch() (string, error) {
+	if w.key != "" {
+		return w.fetchOpenWeather()
+	}
+	body, err := weatherGet(wttrURL + url.PathEscape(w.location) + "?m&format=%t+%C")
+	if err != nil {
+		return "", err
+	}
+	// wttr.in answers "+12°C Partly cloudy"; FIGlet fonts have no degree
+	// sign.
+	text := strings.TrimSpace(strings.ReplaceAll(string(body), "°", ""))
+	if text == "" || strings.Contains(text, "Unknown location") {
+		return "", fmt.Errorf("unknown location %q", w.location)
+	}
+	return strings.TrimPrefix(text, "+"), nil
+}
+
+func (w *weatherFeed) fetchOpenWeather() (string, error) {
+	q := url.Values{"q": {w.location}, "appid": {w.key}, "units": {"metric"}}
+	body, err := weatherGet(openWeatherURL + "?" + q.Encode())
+	if err != nil {
+		return "", err
+	}
+	var r struct {
+		Main    struct{ Temp float64 }
+		Weather []struct{ Main string }
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", err
+	}
+	text := fmt.Sprintf("%dC", int(math.Round(r.Main.Temp)))
+	if len(r.Weather) > 0 {
+		text += " " + r.Weather[0].Main
+	}
+	return text, nil
+}
+
+func weatherGet(u string) ([]byte, error) {
+	resp, err := weatherClient.Get(u)
+	if err != nil {
+		// The error repeats the URL, API key and all.
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+}
+
+// readCache returns the cached reading and when it was fetched.
+func (w *weatherFeed) readCache() (string, time.Time, error) {
+	if w.cache == "" {
+		return "", time.Time{}, os.ErrNotExist
+	}
+	info, err := os.Stat(w.cache)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	b, err := os.ReadFile(w.cache)
+	return string(b), info.ModTime(), err
+}
+
+// writeCache saves a reading; failing to is not worth reporting.
+func (w *weatherFeed) writeCache(text string) {
+	if w.cache == "" {
+		return
+	}
+	err := os.MkdirAll(filepath.Dir(w.cache), 0o755)
+	if err == nil {
+		err = os.WriteFile(w.cache, []byte(text), 0o644)
+	}
+	if err != nil {
+		debugf("weather cache: %v", err)
+	}
+}